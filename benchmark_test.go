@@ -23,6 +23,25 @@ func BenchmarkInsertBack(b *testing.B) {
 	}
 }
 
+func BenchmarkInsertBackWithoutLocking(b *testing.B) {
+	blist := New[int](WithoutLocking[int]())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = blist.InsertBack(i)
+	}
+}
+
+func BenchmarkPopFrontWithoutLocking(b *testing.B) {
+	blist := New[int](WithoutLocking[int]())
+	for i := 0; i < b.N; i++ {
+		_ = blist.InsertBack(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = blist.PopFront()
+	}
+}
+
 func BenchmarkDeleteFront(b *testing.B) {
 	blist := New[int]()
 	for i := 0; i < b.N; i++ {
@@ -91,6 +110,210 @@ func BenchmarkInsertBackWithOptions(b *testing.B) {
 	}
 }
 
+func BenchmarkInsertBackLoop(b *testing.B) {
+	values := make([]int, 1000000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		blist := New[int]()
+		for _, v := range values {
+			_ = blist.InsertBack(v)
+		}
+		_ = blist.Free()
+	}
+}
+
+func BenchmarkInsertBackAll(b *testing.B) {
+	values := make([]int, 1000000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		blist := New[int]()
+		_ = blist.InsertBackAll(values...)
+		_ = blist.Free()
+	}
+}
+
+func BenchmarkFromSlice(b *testing.B) {
+	values := make([]int, 1000000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = FromSlice(values).Free()
+	}
+}
+
+func BenchmarkToSlice(b *testing.B) {
+	blist := FromSlice(make([]int, 1000000))
+	defer blist.Free()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = blist.ToSlice()
+	}
+}
+
+func BenchmarkSPSC(b *testing.B) {
+	q := NewSPSC[int]()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < b.N; {
+			if _, err := q.PopFront(); err == nil {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+	for i := 0; i < b.N; i++ {
+		q.InsertBack(i)
+	}
+	<-done
+}
+
+func BenchmarkMutexSPSC(b *testing.B) {
+	blist := New[int]()
+	defer blist.Free()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < b.N; {
+			if err := blist.DeleteFront(); err == nil {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+	for i := 0; i < b.N; i++ {
+		_ = blist.InsertBack(i)
+	}
+	<-done
+}
+
+func BenchmarkSnapshotTraverse(b *testing.B) {
+	blist := New[int]()
+	for i := 0; i < 1000; i++ {
+		_ = blist.InsertBack(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		view := blist.Snapshot()
+		view.TraverseForward(func(data int) {})
+		view.Release()
+	}
+}
+
+func BenchmarkSnapshotWrite(b *testing.B) {
+	blist := New[int]()
+	for i := 0; i < 1000; i++ {
+		_ = blist.InsertBack(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		view := blist.Snapshot()
+		_ = blist.InsertBack(i)
+		_ = blist.DeleteFront()
+		view.Release()
+	}
+}
+
+// Each step keeps the previous version intact, as an undo stack would.
+func BenchmarkPersistentPushBack(b *testing.B) {
+	p := NewPersistent[int]()
+	for i := 0; i < 1000; i++ {
+		p, _ = p.PushBack(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		next, _ := p.PushBack(i)
+		_, p, _ = next.PopFront()
+	}
+}
+
+func BenchmarkClonePushBack(b *testing.B) {
+	blist := New[int]()
+	for i := 0; i < 1000; i++ {
+		_ = blist.InsertBack(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		next, _ := blist.Clone()
+		_ = next.InsertBack(i)
+		_ = next.DeleteFront()
+		_ = blist.Free()
+		blist = next
+	}
+}
+
+func BenchmarkMarshalBinary(b *testing.B) {
+	blist := New[int]()
+	for i := 0; i < 1000; i++ {
+		_ = blist.InsertBack(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = blist.MarshalBinary()
+	}
+}
+
+func BenchmarkUnmarshalBinary(b *testing.B) {
+	blist := New[int]()
+	for i := 0; i < 1000; i++ {
+		_ = blist.InsertBack(i)
+	}
+	data, _ := blist.MarshalBinary()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = blist.UnmarshalBinary(data)
+	}
+}
+
+func BenchmarkMarshalJSON(b *testing.B) {
+	blist := New[int]()
+	for i := 0; i < 1000; i++ {
+		_ = blist.InsertBack(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = blist.MarshalJSON()
+	}
+}
+
+func BenchmarkDequeParallel(b *testing.B) {
+	deque := NewDeque[int]()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			switch i % 4 {
+			case 0:
+				deque.InsertBack(i)
+			case 1:
+				deque.InsertFront(i)
+			case 2:
+				_, _ = deque.PopFront()
+			default:
+				_, _ = deque.PopBack()
+			}
+		}
+	})
+}
+
+func BenchmarkMutexDequeParallel(b *testing.B) {
+	blist := New[int]()
+	defer blist.Free()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			switch i % 4 {
+			case 0:
+				_ = blist.InsertBack(i)
+			case 1:
+				_ = blist.InsertFront(i)
+			case 2:
+				_ = blist.DeleteFront()
+			default:
+				_ = blist.DeleteBack()
+			}
+		}
+	})
+}
+
 // Benchmark SLICE
 type Slice struct {
 	data []int
@@ -0,0 +1,321 @@
+package XLL
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+	"strings"
+)
+
+// Codec encodes and decodes single elements for MarshalBinary, WriteTo
+// and their counterparts.
+type Codec[T any] interface {
+	Encode(w io.Writer, v T) error
+	Decode(r io.Reader) (T, error)
+}
+
+// WithCodec sets the codec used to encode elements. Without one, lists of
+// strings, ints, uints and fixed-size types as understood by
+// encoding/binary are encoded in little-endian order, and any other
+// element type fails with ErrNoCodec.
+func WithCodec[T any](codec Codec[T]) Option[T] {
+	return func(list *XLL[T]) {
+		list.codec = codec
+	}
+}
+
+// The binary form is a header, the elements as written by the codec, and
+// a CRC-32 (IEEE) of everything before it, all little-endian.
+const binaryVersion = 1
+
+var binaryMagic = [4]byte{'X', 'L', 'L', 'B'}
+
+type binaryHeader struct {
+	Magic      [4]byte
+	Version    uint16
+	Count      uint64
+	BlockSize  uint64
+	GrowthRate float64
+}
+
+// MarshalBinary encodes the list as WriteTo does.
+func (list *XLL[T]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := list.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary replaces the list's contents with those encoded in
+// data, as ReadFrom does. Bytes left over after the encoding are an
+// error.
+func (list *XLL[T]) UnmarshalBinary(data []byte) error {
+	codec, err := list.elementCodec()
+	if err != nil {
+		return err
+	}
+	r := bytes.NewReader(data)
+	values, header, _, err := decode(r, codec)
+	if err != nil {
+		return err
+	}
+	if r.Len() > 0 {
+		return fmt.Errorf("%w: %d trailing bytes", ErrCorrupt, r.Len())
+	}
+	list.lock()
+	defer list.unlock()
+	if list.IsFreed() {
+		return ErrFreedList
+	}
+	return list.replace(values, int(header.BlockSize), header.GrowthRate)
+}
+
+// WriteTo writes the list's block size, growth rate and elements to w. w
+// is written to in small pieces, so it is worth buffering.
+func (list *XLL[T]) WriteTo(w io.Writer) (int64, error) {
+	list.rlock()
+	defer list.runlock()
+	if list.IsFreed() {
+		return 0, ErrFreedList
+	}
+	codec, err := list.elementCodec()
+	if err != nil {
+		return 0, err
+	}
+	return list.encode(w, codec)
+}
+
+// encode writes the binary form of the list using codec. The caller must
+// hold list.mu.
+func (list *XLL[T]) encode(w io.Writer, codec Codec[T]) (int64, error) {
+	cw := &checksumWriter{w: w, crc: crc32.NewIEEE()}
+	header := binaryHeader{
+		Magic:      binaryMagic,
+		Version:    binaryVersion,
+		Count:      uint64(list.size),
+		BlockSize:  uint64(list.blockSize),
+		GrowthRate: list.growthRate,
+	}
+	if err := binary.Write(cw, binary.LittleEndian, &header); err != nil {
+		return cw.n, err
+	}
+	var prev uintptr
+	for curr := list.head; curr != 0; {
+		node := list.node(curr)
+		if err := codec.Encode(cw, node.data); err != nil {
+			return cw.n, err
+		}
+		prev, curr = curr, XOR(prev, node.both)
+	}
+	err := binary.Write(cw.w, binary.LittleEndian, cw.crc.Sum32())
+	if err == nil {
+		cw.n += 4
+	}
+	return cw.n, err
+}
+
+// ReadFrom reads a list written by WriteTo from r, replacing the list's
+// contents and taking on its block size and growth rate. r is read up to
+// the end of the encoding and no further. Input that is truncated, fails
+// the checksum or is otherwise malformed returns an error wrapping
+// ErrCorrupt, and leaves the list as it was.
+//
+// ReadFrom also works on the zero value of XLL, which then keeps its
+// blocks unpinned as it has no finalizer to unpin them.
+func (list *XLL[T]) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := list.elementCodec()
+	if err != nil {
+		return 0, err
+	}
+	values, header, n, err := decode(r, codec)
+	if err != nil {
+		return n, err
+	}
+	list.lock()
+	defer list.unlock()
+	if list.IsFreed() {
+		return n, ErrFreedList
+	}
+	return n, list.replace(values, int(header.BlockSize), header.GrowthRate)
+}
+
+// decode reads and checks an encoded list, returning the elements, the
+// header and the number of bytes read.
+func decode[T any](r io.Reader, codec Codec[T]) ([]T, binaryHeader, int64, error) {
+	cr := &checksumReader{r: r, crc: crc32.NewIEEE()}
+	values, header, err := decodeBinary(cr, codec)
+	if err != nil {
+		return nil, header, cr.n, err
+	}
+	var sum uint32
+	if err := binary.Read(cr.r, binary.LittleEndian, &sum); err != nil {
+		return nil, header, cr.n, corrupt("reading checksum", err)
+	}
+	cr.n += 4
+	if sum != cr.crc.Sum32() {
+		return nil, header, cr.n, fmt.Errorf("%w: checksum mismatch", ErrCorrupt)
+	}
+	return values, header, cr.n, nil
+}
+
+// decodeBinary reads the header and elements of an encoded list.
+func decodeBinary[T any](r io.Reader, codec Codec[T]) ([]T, binaryHeader, error) {
+	var header binaryHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, header, corrupt("reading header", err)
+	}
+	switch {
+	case header.Magic != binaryMagic:
+		return nil, header, fmt.Errorf("%w: bad magic %q", ErrCorrupt, header.Magic[:])
+	case header.Version != binaryVersion:
+		return nil, header, fmt.Errorf("%w: unsupported version %d", ErrCorrupt, header.Version)
+	case header.Count > math.MaxInt, header.BlockSize == 0, header.BlockSize > math.MaxInt:
+		return nil, header, fmt.Errorf("%w: bad header", ErrCorrupt)
+	case !(header.GrowthRate > 1) || math.IsInf(header.GrowthRate, 0):
+		return nil, header, fmt.Errorf("%w: bad growth rate %v", ErrCorrupt, header.GrowthRate)
+	}
+
+	// The count is not trusted until the checksum is, so memory grows
+	// with the elements actually read.
+	values := make([]T, 0, min(header.Count, 1024))
+	for i := uint64(0); i < header.Count; i++ {
+		v, err := codec.Decode(r)
+		if err != nil {
+			return nil, header, corrupt(fmt.Sprintf("decoding element %d", i), err)
+		}
+		values = append(values, v)
+	}
+	return values, header, nil
+}
+
+// corrupt describes a failure to read an encoded list. Running out of
+// input means the input is corrupt; other errors are passed on.
+func corrupt(what string, err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return fmt.Errorf("%w: %s: unexpected end of input", ErrCorrupt, what)
+	}
+	return fmt.Errorf("%s: %w", what, err)
+}
+
+// replace swaps the list's contents for values and takes on the given
+// configuration. The caller must hold list.mu.
+func (list *XLL[T]) replace(values []T, blockSize int, growthRate float64) error {
+	if list.maxSize > 0 && list.policy == OverflowReject && len(values) > list.maxSize {
+		return ErrFullList
+	}
+	// A file-backed list keeps its arena through clear, so growing it
+	// first leaves the list intact if the file cannot grow.
+	if list.file != nil {
+		if err := list.reserve(len(values) - list.size); err != nil {
+			return err
+		}
+	}
+	list.modified()
+	list.clear()
+	list.blockSize = blockSize
+	list.growthRate = growthRate
+	if len(values) == 0 {
+		return nil
+	}
+	return list.pushBack(values)
+}
+
+// elementCodec returns the codec for the list's elements.
+func (list *XLL[T]) elementCodec() (Codec[T], error) {
+	if list.codec != nil {
+		return list.codec, nil
+	}
+	var zero T
+	switch any(zero).(type) {
+	case int, uint, string:
+	default:
+		if binary.Size(zero) <= 0 {
+			return nil, fmt.Errorf("%w %T", ErrNoCodec, zero)
+		}
+	}
+	return basicCodec[T]{}, nil
+}
+
+// basicCodec encodes ints and uints as 64 bits, strings as a 64-bit
+// length and their bytes, and anything else with encoding/binary.
+type basicCodec[T any] struct{}
+
+func (basicCodec[T]) Encode(w io.Writer, v T) error {
+	switch x := any(v).(type) {
+	case int:
+		return binary.Write(w, binary.LittleEndian, int64(x))
+	case uint:
+		return binary.Write(w, binary.LittleEndian, uint64(x))
+	case string:
+		if err := binary.Write(w, binary.LittleEndian, uint64(len(x))); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, x)
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, v)
+}
+
+func (basicCodec[T]) Decode(r io.Reader) (T, error) {
+	var v T
+	switch p := any(&v).(type) {
+	case *int:
+		var x int64
+		err := binary.Read(r, binary.LittleEndian, &x)
+		*p = int(x)
+		return v, err
+	case *uint:
+		var x uint64
+		err := binary.Read(r, binary.LittleEndian, &x)
+		*p = uint(x)
+		return v, err
+	case *string:
+		var n uint64
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return v, err
+		}
+		// Copy rather than allocate n bytes up front, as n may be
+		// corrupt.
+		var b strings.Builder
+		if _, err := io.CopyN(&b, r, int64(min(n, math.MaxInt64))); err != nil {
+			return v, err
+		}
+		*p = b.String()
+		return v, nil
+	}
+	err := binary.Read(r, binary.LittleEndian, &v)
+	return v, err
+}
+
+// checksumWriter counts and checksums what passes through it.
+type checksumWriter struct {
+	w   io.Writer
+	crc hash.Hash32
+	n   int64
+}
+
+func (cw *checksumWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.crc.Write(p[:n])
+	cw.n += int64(n)
+	return n, err
+}
+
+// checksumReader counts and checksums what passes through it.
+type checksumReader struct {
+	r   io.Reader
+	crc hash.Hash32
+	n   int64
+}
+
+func (cr *checksumReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.crc.Write(p[:n])
+	cr.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,209 @@
+package XLL
+
+import "unsafe"
+
+// Nodes are addressed by links: the node's address, or in index mode its
+// slot in the arena plus one. Either way 0 means no node, and a node's
+// both field holds the XOR of its neighbours' links.
+
+// node resolves a link to the node it names.
+func (list *XLL[T]) node(link uintptr) *Node[T] {
+	if list.indexed {
+		return &list.blocks.nodes[link-1]
+	}
+	return nodeAt[T](link)
+}
+
+// nodeAt converts an address link back into a node pointer.
+//
+// Links hold addresses the compiler cannot trace back to an allocation,
+// so pointer checking is disabled here rather than at every call site;
+// the owner's chain of blocks keeps the nodes alive.
+//
+//go:nocheckptr
+func nodeAt[T any](link uintptr) *Node[T] {
+	return (*Node[T])(unsafe.Pointer(link))
+}
+
+// link returns the link naming slot i of block.
+func (list *XLL[T]) link(block *Block[T], i int) uintptr {
+	if list.indexed {
+		return uintptr(i + 1)
+	}
+	return uintptr(unsafe.Pointer(&block.nodes[i]))
+}
+
+// take hands out a slot, preferring dead slots over fresh ones.
+func (block *Block[T]) take() int {
+	i := len(block.nodes)
+	if block.free != 0 {
+		i = block.free - 1
+		block.free = int(block.nodes[i].both)
+	} else {
+		block.nodes = block.nodes[:i+1]
+	}
+	block.nodes[i].both = 0
+	block.live++
+	return i
+}
+
+// put marks slot i dead and threads it onto the free list through its
+// both field.
+func (block *Block[T]) put(i int) {
+	var zero T
+	node := &block.nodes[i]
+	node.data = zero
+	node.both = uintptr(block.free)
+	block.free = i + 1
+	block.live--
+}
+
+// slot returns the index of the node at addr within the block, or -1 if
+// the node lives elsewhere.
+func (block *Block[T]) slot(addr uintptr) int {
+	base := uintptr(unsafe.Pointer(unsafe.SliceData(block.nodes)))
+	if addr < base {
+		return -1
+	}
+	if i := (addr - base) / unsafe.Sizeof(Node[T]{}); i < uintptr(len(block.nodes)) {
+		return int(i)
+	}
+	return -1
+}
+
+// addBlock puts a new block holding capacity nodes at the front of the
+// chain, where allocNode looks first. The caller must hold list.mu.
+func (list *XLL[T]) addBlock(capacity int) *Block[T] {
+	block := &Block[T]{nodes: make([]Node[T], 0, capacity)}
+	if list.pinning && !list.indexed {
+		block.pinner.Pin(unsafe.SliceData(block.nodes))
+	}
+	block.next = list.blocks
+	list.blocks = block
+	list.capacity += capacity
+	return block
+}
+
+// growArena moves the index mode arena into a slice of the given
+// capacity. Links are slot numbers, so nothing needs relinking. Only the
+// arena of a file-backed list can fail to grow. The caller must hold
+// list.mu.
+func (list *XLL[T]) growArena(capacity int) error {
+	if list.file != nil {
+		return list.growFile(capacity)
+	}
+	if list.blocks == nil {
+		list.addBlock(capacity)
+		return nil
+	}
+	arena := list.blocks
+	nodes := make([]Node[T], len(arena.nodes), capacity)
+	copy(nodes, arena.nodes)
+	arena.nodes = nodes
+	list.capacity = capacity
+	return nil
+}
+
+// reserve makes room for n more nodes, adding at most one block, sized to
+// fit. The caller must hold list.mu.
+func (list *XLL[T]) reserve(n int) error {
+	need := list.size + n - list.capacity
+	switch {
+	case need <= 0:
+		return nil
+	case !list.indexed:
+		list.addBlock(need)
+		return nil
+	case list.blocks == nil:
+		return list.growArena(n)
+	}
+	return list.growArena(max(list.size+n, list.nextBlockSize()))
+}
+
+// makeRoom reserves room for n insertions before the list is changed, so
+// that a file-backed list that cannot grow fails while still intact. A
+// bounded list never needs more than its bound. The caller must hold
+// list.mu.
+func (list *XLL[T]) makeRoom(n int) error {
+	if list.maxSize > 0 {
+		n = min(n, list.maxSize-list.size)
+	}
+	return list.reserve(n)
+}
+
+// grow makes room for one more node if the list is full, growing its
+// storage geometrically. The caller must hold list.mu.
+func (list *XLL[T]) grow() error {
+	switch {
+	case list.size < list.capacity:
+		return nil
+	case list.indexed:
+		return list.growArena(list.nextBlockSize())
+	}
+	list.addBlock(list.nextBlockSize())
+	return nil
+}
+
+// nextBlockSize grows blocks geometrically. Outside index mode a block is
+// never made larger than the number of live nodes, so a list that drains
+// and refills keeps reusing memory of roughly its own size instead of
+// doubling forever.
+func (list *XLL[T]) nextBlockSize() int {
+	if list.blocks == nil {
+		return list.blockSize
+	}
+	capacity := int(float64(cap(list.blocks.nodes)) * list.growthRate)
+	if list.indexed {
+		return max(list.blockSize, capacity)
+	}
+	return max(list.blockSize, min(capacity, list.size))
+}
+
+// allocNode takes a slot for data, counts it as live and returns its
+// link. It fails only if the list has no room and cannot grow, which
+// callers that must not fail halfway rule out with grow or reserve
+// first. The caller must hold list.mu.
+func (list *XLL[T]) allocNode(data T) (uintptr, error) {
+	if err := list.grow(); err != nil {
+		return 0, err
+	}
+	block := list.blocks
+	for block.live == cap(block.nodes) {
+		block = block.next
+	}
+	i := block.take()
+	block.nodes[i].data = data
+	list.size++
+	return list.link(block, i), nil
+}
+
+// release returns the slot held by an unlinked node. A block whose nodes
+// are all dead is unpinned and dropped from the chain, except for the
+// newest one, which is kept and rewound for the next insertions. The
+// caller must hold list.mu.
+func (list *XLL[T]) release(link uintptr) {
+	var prev *Block[T]
+	for block := list.blocks; block != nil; prev, block = block, block.next {
+		i := int(link) - 1
+		if !list.indexed {
+			i = block.slot(link)
+		}
+		if i < 0 {
+			continue
+		}
+		block.put(i)
+		list.size--
+		if block.live > 0 {
+			return
+		}
+		if prev == nil {
+			block.nodes = block.nodes[:0]
+			block.free = 0
+			return
+		}
+		prev.next = block.next
+		block.pinner.Unpin()
+		list.capacity -= cap(block.nodes)
+		return
+	}
+}
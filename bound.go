@@ -0,0 +1,57 @@
+package XLL
+
+// OverflowPolicy says what a list bounded by WithMaxSize does with an
+// element inserted at one of its ends while it is full.
+type OverflowPolicy int
+
+const (
+	// OverflowReject fails the insertion with ErrFullList.
+	OverflowReject OverflowPolicy = iota
+	// OverflowDropOldest evicts an element from the opposite end to make
+	// room, turning the list into a ring buffer of the latest elements.
+	OverflowDropOldest
+	// OverflowDropNewest silently discards the inserted element.
+	OverflowDropNewest
+)
+
+// WithMaxSize bounds the list to n elements and sets what happens to
+// insertions at either end once it is full. Positional and cursor
+// insertions, which have no opposite end, return ErrFullList under every
+// policy. PushFrontWait and PushBackWait wait for room under
+// OverflowReject.
+func WithMaxSize[T any](n int, policy OverflowPolicy) Option[T] {
+	return func(list *XLL[T]) {
+		if n > 0 {
+			list.maxSize = n
+			list.policy = policy
+		}
+	}
+}
+
+// fit applies the overflow policy to n elements about to be inserted at
+// one end, evicting from the other end as needed. Counting in the order
+// they would enter, the first skip are dropped and the next keep are to
+// be inserted. The caller must hold list.mu.
+func (list *XLL[T]) fit(n int, front bool) (skip, keep int, err error) {
+	if list.maxSize == 0 || list.size+n <= list.maxSize {
+		return 0, n, nil
+	}
+	switch list.policy {
+	case OverflowDropOldest:
+		keep = min(n, list.maxSize)
+		list.trim(list.size+keep-list.maxSize, !front)
+		return n - keep, keep, nil
+	case OverflowDropNewest:
+		return 0, list.maxSize - list.size, nil
+	default:
+		return 0, 0, ErrFullList
+	}
+}
+
+// trim removes up to n elements from one end. The caller must hold
+// list.mu.
+func (list *XLL[T]) trim(n int, front bool) {
+	for ; n > 0 && list.head != 0; n-- {
+		list.pop(front)
+	}
+}
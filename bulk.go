@@ -0,0 +1,107 @@
+package XLL
+
+// FromSlice returns a new list holding the elements of s in order. The
+// nodes are allocated as a single block. If s does not fit a bound set by
+// WithMaxSize, its overflow policy decides which elements are kept; under
+// OverflowReject the list stays empty.
+func FromSlice[T any](s []T, options ...Option[T]) *XLL[T] {
+	list := New[T](options...)
+	_ = list.InsertBackAll(s...)
+	return list
+}
+
+// InsertBackAll inserts values at the back in order, under a single lock
+// and with at most one new block.
+func (list *XLL[T]) InsertBackAll(values ...T) error {
+	list.lock()
+	defer list.unlock()
+	if list.IsFreed() {
+		return ErrFreedList
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return list.pushBack(values)
+}
+
+// pushBack links values in at the back, applying the overflow policy.
+// The caller must hold list.mu.
+func (list *XLL[T]) pushBack(values []T) error {
+	if err := list.makeRoom(len(values)); err != nil {
+		return err
+	}
+	skip, keep, err := list.fit(len(values), false)
+	if err != nil || keep == 0 {
+		return err
+	}
+	values = values[skip : skip+keep]
+	list.modified()
+	if err := list.reserve(len(values)); err != nil {
+		return err
+	}
+	for _, v := range values {
+		link, err := list.allocNode(v)
+		if err != nil {
+			return err
+		}
+		list.splice(link, list.tail, 0)
+	}
+	return nil
+}
+
+// InsertFrontAll inserts values at the front so that they keep their
+// order, leaving values[0] as the front element.
+func (list *XLL[T]) InsertFrontAll(values ...T) error {
+	list.lock()
+	defer list.unlock()
+	if list.IsFreed() {
+		return ErrFreedList
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	if err := list.makeRoom(len(values)); err != nil {
+		return err
+	}
+	// Values enter the list back to front.
+	skip, keep, err := list.fit(len(values), true)
+	if err != nil || keep == 0 {
+		return err
+	}
+	values = values[len(values)-skip-keep : len(values)-skip]
+	list.modified()
+	if err := list.reserve(len(values)); err != nil {
+		return err
+	}
+	for i := len(values) - 1; i >= 0; i-- {
+		link, err := list.allocNode(values[i])
+		if err != nil {
+			return err
+		}
+		list.splice(link, 0, list.head)
+	}
+	return nil
+}
+
+// ToSlice returns the elements from front to back.
+func (list *XLL[T]) ToSlice() ([]T, error) {
+	list.rlock()
+	defer list.runlock()
+	if list.IsFreed() {
+		return nil, ErrFreedList
+	}
+	return list.elements(), nil
+}
+
+// elements copies the elements from front to back. The caller must hold
+// list.mu.
+func (list *XLL[T]) elements() []T {
+	s := make([]T, 0, list.size)
+	var prev uintptr
+	for curr := list.head; curr != 0; {
+		node := list.node(curr)
+		s = append(s, node.data)
+		prev, curr = curr, XOR(prev, node.both)
+	}
+	return s
+}
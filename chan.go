@@ -0,0 +1,75 @@
+package XLL
+
+import "context"
+
+// Feed inserts everything received from ch at the back until ch is closed,
+// which returns nil, or ctx is done, which returns the context's error.
+// On a list bounded by WithMaxSize it waits for room as PushBackWait does.
+// If the list is freed meanwhile Feed returns ErrFreedList, dropping the
+// element it was about to insert.
+func (list *XLL[T]) Feed(ctx context.Context, ch <-chan T) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case data, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := list.PushBackWait(ctx, data); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Stream sends the elements from front to back on the returned channel,
+// which is closed once they have all been sent, ctx is done or the list
+// is freed. The elements are those the list held when Stream was called;
+// the list is not locked while the receiver takes them.
+func (list *XLL[T]) Stream(ctx context.Context) <-chan T {
+	ch := make(chan T)
+	s, err := list.ToSlice()
+	go func() {
+		defer close(ch)
+		if err != nil {
+			return
+		}
+		for _, data := range s {
+			if list.IsFreed() {
+				return
+			}
+			select {
+			case ch <- data:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// Drain pops elements from the front and sends them on the returned
+// channel, waiting for more as PopFrontWait does whenever the list is
+// empty. The channel is closed once ctx is done or the list is freed. An
+// element popped while ctx ends is put back at the front, so nothing
+// leaves the list without being received.
+func (list *XLL[T]) Drain(ctx context.Context) <-chan T {
+	ch := make(chan T)
+	go func() {
+		defer close(ch)
+		for {
+			data, err := list.PopFrontWait(ctx)
+			if err != nil {
+				return
+			}
+			select {
+			case ch <- data:
+			case <-ctx.Done():
+				_ = list.InsertFront(data)
+				return
+			}
+		}
+	}()
+	return ch
+}
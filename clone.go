@@ -0,0 +1,42 @@
+package XLL
+
+// Clone returns a copy of the list configured like it, with the elements
+// in a single block of exactly the right size. Elements are copied by
+// assignment, so a clone of a list of pointers shares what they point to.
+func (list *XLL[T]) Clone() (*XLL[T], error) {
+	return list.clone(nil)
+}
+
+// CloneFunc is like Clone but stores copy(v) for every element v, which
+// lets it deep-copy elements holding pointers. copy runs under the list's
+// read lock and must not modify the list.
+func (list *XLL[T]) CloneFunc(copy func(T) T) (*XLL[T], error) {
+	return list.clone(copy)
+}
+
+func (list *XLL[T]) clone(copy func(T) T) (*XLL[T], error) {
+	list.rlock()
+	defer list.runlock()
+	if list.IsFreed() {
+		return nil, ErrFreedList
+	}
+	clone := list.sibling()
+	if err := clone.reserve(list.size); err != nil {
+		return nil, err
+	}
+	var prev uintptr
+	for curr := list.head; curr != 0; {
+		node := list.node(curr)
+		data := node.data
+		if copy != nil {
+			data = copy(data)
+		}
+		link, err := clone.allocNode(data)
+		if err != nil {
+			return nil, err
+		}
+		clone.splice(link, clone.tail, 0)
+		prev, curr = curr, XOR(prev, node.both)
+	}
+	return clone, nil
+}
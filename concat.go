@@ -0,0 +1,156 @@
+package XLL
+
+import "unsafe"
+
+// Append moves every element of other to the back of the list, leaving
+// other empty but usable. A list bounded by WithMaxSize applies its
+// overflow policy to the elements that do not fit. When both lists link
+// nodes by address this takes constant time: the two boundary nodes are
+// linked to each other and other's blocks, pins included, join the
+// list's chain. Otherwise the elements are copied over.
+func (list *XLL[T]) Append(other *XLL[T]) error {
+	return list.concat(other, false)
+}
+
+// Prepend moves every element of other to the front of the list, leaving
+// other empty but usable, in the same way as Append.
+func (list *XLL[T]) Prepend(other *XLL[T]) error {
+	return list.concat(other, true)
+}
+
+func (list *XLL[T]) concat(other *XLL[T], front bool) error {
+	if list == other {
+		return ErrSameList
+	}
+	lockPair(list, other)
+	defer unlockPair(list, other)
+	if list.IsFreed() || other.IsFreed() {
+		return ErrFreedList
+	}
+	if other.head == 0 {
+		return nil
+	}
+	if list.policy == OverflowReject {
+		if err := list.room(other.size); err != nil {
+			return err
+		}
+	}
+	copying := list.indexed || other.indexed
+	if copying {
+		if err := list.reserve(other.size); err != nil {
+			return err
+		}
+	}
+
+	list.modified()
+	other.modified()
+	// A policy that drops elements applies once they have all moved:
+	// DropOldest trims the end other's elements did not join, DropNewest
+	// the end they did.
+	if list.maxSize > 0 {
+		defer func() {
+			list.trim(list.size-list.maxSize, (list.policy == OverflowDropOldest) != front)
+		}()
+	}
+	if copying {
+		if err := list.copyFrom(other, front); err != nil {
+			return err
+		}
+		other.clear()
+		return nil
+	}
+
+	head, tail := other.head, other.tail
+	switch {
+	case list.head == 0:
+		list.head, list.tail = head, tail
+	case front:
+		list.flipLink(tail, list.head)
+		list.head = head
+	default:
+		list.flipLink(list.tail, head)
+		list.tail = tail
+	}
+	list.adopt(other)
+	return nil
+}
+
+// flipLink makes the back node left and the front node right neighbours,
+// or, as XOR undoes itself, cuts the link between two neighbours. The
+// caller must hold list.mu.
+func (list *XLL[T]) flipLink(left, right uintptr) {
+	node := list.node(left)
+	node.both = XOR(node.both, right)
+	node = list.node(right)
+	node.both = XOR(node.both, left)
+}
+
+// adopt moves other's blocks and their pins to the end of the list's
+// chain, so the list's newest block stays the one it allocates from.
+// Blocks without live nodes are released instead, and a list that does
+// not pin its blocks unpins the ones it takes. Both lists must be locked.
+func (list *XLL[T]) adopt(other *XLL[T]) {
+	last := &list.blocks
+	for *last != nil {
+		last = &(*last).next
+	}
+	for block := other.blocks; block != nil; {
+		next := block.next
+		block.next = nil
+		if block.live == 0 || !list.pinning {
+			block.pinner.Unpin()
+		}
+		if block.live > 0 {
+			*last = block
+			last = &block.next
+			list.capacity += cap(block.nodes)
+		}
+		block = next
+	}
+	list.size += other.size
+
+	other.head = 0
+	other.tail = 0
+	other.blocks = nil
+	other.size = 0
+	other.capacity = 0
+}
+
+// copyFrom links copies of other's elements onto one end of the list,
+// keeping their order. Both lists must be locked.
+func (list *XLL[T]) copyFrom(other *XLL[T], front bool) error {
+	var prev uintptr
+	curr := other.head
+	if front {
+		curr = other.tail
+	}
+	for curr != 0 {
+		node := other.node(curr)
+		link, err := list.allocNode(node.data)
+		if err != nil {
+			return err
+		}
+		if front {
+			list.splice(link, 0, list.head)
+		} else {
+			list.splice(link, list.tail, 0)
+		}
+		prev, curr = curr, XOR(prev, node.both)
+	}
+	return nil
+}
+
+// lockPair locks two distinct lists in address order, so that lists
+// locking each other concurrently cannot deadlock.
+func lockPair[T any](a, b *XLL[T]) {
+	if uintptr(unsafe.Pointer(a)) > uintptr(unsafe.Pointer(b)) {
+		a, b = b, a
+	}
+	a.lock()
+	b.lock()
+}
+
+func unlockPair[T any](a, b *XLL[T]) {
+	a.unlock()
+	b.unlock()
+}
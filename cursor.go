@@ -0,0 +1,234 @@
+package XLL
+
+// Cursor is a position in the list that can move in both directions and
+// edit the list in place. Because a node's both field only yields a
+// neighbour when the other neighbour is known, the cursor keeps the link
+// of the node in front of the current one alongside it.
+//
+// A cursor becomes stale once the list is modified by anything other
+// than the cursor itself; its methods then return ErrStaleCursor.
+type Cursor[T any] struct {
+	list    *XLL[T]
+	prev    uintptr
+	curr    uintptr
+	version uint64
+	err     error // why the last Next or Prev failed, if not at an end
+}
+
+// CursorFront returns a cursor on the front element. On an empty list the
+// cursor has no element, but can still be used to insert one.
+func (list *XLL[T]) CursorFront() *Cursor[T] {
+	list.rlock()
+	defer list.runlock()
+	return &Cursor[T]{list: list, curr: list.head, version: list.version}
+}
+
+// CursorBack returns a cursor on the back element.
+func (list *XLL[T]) CursorBack() *Cursor[T] {
+	list.rlock()
+	defer list.runlock()
+	c := &Cursor[T]{list: list, curr: list.tail, version: list.version}
+	if c.curr != 0 {
+		c.prev = list.node(c.curr).both
+	}
+	return c
+}
+
+// check reports why the cursor can't be used. The caller must hold
+// list.mu.
+func (c *Cursor[T]) check() error {
+	if c.list.IsFreed() {
+		return ErrFreedList
+	}
+	if c.version != c.list.version {
+		return ErrStaleCursor
+	}
+	return nil
+}
+
+// edited records a modification made through the cursor, keeping the
+// cursor valid while staling every other one.
+func (c *Cursor[T]) edited() {
+	c.list.modified()
+	c.version = c.list.version
+}
+
+// Next moves the cursor one element towards the back. It returns false,
+// leaving the cursor in place, if there is no next element or the cursor
+// is stale; Err then tells the two apart.
+func (c *Cursor[T]) Next() bool {
+	c.list.rlock()
+	defer c.list.runlock()
+	if c.err = c.check(); c.err != nil || c.curr == 0 {
+		return false
+	}
+	next := XOR(c.prev, c.list.node(c.curr).both)
+	if next == 0 {
+		return false
+	}
+	c.prev, c.curr = c.curr, next
+	return true
+}
+
+// Prev moves the cursor one element towards the front. It returns false,
+// leaving the cursor in place, if there is no previous element or the
+// cursor is stale; Err then tells the two apart.
+func (c *Cursor[T]) Prev() bool {
+	c.list.rlock()
+	defer c.list.runlock()
+	if c.err = c.check(); c.err != nil || c.prev == 0 {
+		return false
+	}
+	c.prev, c.curr = XOR(c.list.node(c.prev).both, c.curr), c.prev
+	return true
+}
+
+// Err returns ErrStaleCursor or ErrFreedList if that is why the last Next
+// or Prev returned false, and nil if it reached an end of the list, so a
+// loop over Next can tell a finished walk from an interrupted one.
+func (c *Cursor[T]) Err() error {
+	return c.err
+}
+
+// Value returns the element under the cursor.
+func (c *Cursor[T]) Value() (T, error) {
+	c.list.rlock()
+	defer c.list.runlock()
+	var zero T
+	if err := c.check(); err != nil {
+		return zero, err
+	}
+	if c.curr == 0 {
+		return zero, ErrEmptyList
+	}
+	return c.list.node(c.curr).data, nil
+}
+
+// Set replaces the element under the cursor.
+func (c *Cursor[T]) Set(data T) error {
+	c.list.lock()
+	defer c.list.unlock()
+	if err := c.check(); err != nil {
+		return err
+	}
+	if c.curr == 0 {
+		return ErrEmptyList
+	}
+	c.edited()
+	c.list.node(c.curr).data = data
+	return nil
+}
+
+// InsertBefore inserts data in front of the element under the cursor. The
+// cursor stays where it is, or moves onto data if the list was empty.
+func (c *Cursor[T]) InsertBefore(data T) error {
+	return c.insert(data, true)
+}
+
+// InsertAfter inserts data behind the element under the cursor. The
+// cursor stays where it is, or moves onto data if the list was empty.
+func (c *Cursor[T]) InsertAfter(data T) error {
+	return c.insert(data, false)
+}
+
+func (c *Cursor[T]) insert(data T, before bool) error {
+	list := c.list
+	list.lock()
+	defer list.unlock()
+	if err := c.check(); err != nil {
+		return err
+	}
+	if err := list.room(1); err != nil {
+		return err
+	}
+	if err := list.grow(); err != nil {
+		return err
+	}
+
+	c.edited()
+	link, err := list.allocNode(data)
+	if err != nil {
+		return err
+	}
+	if c.curr == 0 {
+		list.head = link
+		list.tail = link
+		c.curr = link
+		return nil
+	}
+
+	left, right := c.prev, c.curr
+	if !before {
+		left, right = c.curr, XOR(c.prev, list.node(c.curr).both)
+	}
+	list.splice(link, left, right)
+	if before {
+		c.prev = link
+	}
+	return nil
+}
+
+// splice links a free node in between left and right, either of which
+// may be 0 at the ends of the list. The caller must hold list.mu.
+func (list *XLL[T]) splice(link, left, right uintptr) {
+	list.node(link).both = XOR(left, right)
+	if left != 0 {
+		node := list.node(left)
+		node.both = XOR(node.both, XOR(right, link))
+	} else {
+		list.head = link
+	}
+	if right != 0 {
+		node := list.node(right)
+		node.both = XOR(node.both, XOR(left, link))
+	} else {
+		list.tail = link
+	}
+}
+
+// unsplice unlinks the node between left and right and releases it. The
+// caller must hold list.mu.
+func (list *XLL[T]) unsplice(link, left, right uintptr) {
+	if left != 0 {
+		node := list.node(left)
+		node.both = XOR(node.both, XOR(link, right))
+	} else {
+		list.head = right
+	}
+	if right != 0 {
+		node := list.node(right)
+		node.both = XOR(node.both, XOR(link, left))
+	} else {
+		list.tail = left
+	}
+	list.release(link)
+}
+
+// Remove deletes the element under the cursor and moves the cursor to the
+// next element, or to the previous one if it removed the back element.
+func (c *Cursor[T]) Remove() error {
+	list := c.list
+	list.lock()
+	defer list.unlock()
+	if err := c.check(); err != nil {
+		return err
+	}
+	if c.curr == 0 {
+		return ErrEmptyList
+	}
+
+	c.edited()
+	next := XOR(c.prev, list.node(c.curr).both)
+	list.unsplice(c.curr, c.prev, next)
+	if next != 0 {
+		c.curr = next
+		return nil
+	}
+	c.curr = c.prev
+	if c.prev != 0 {
+		// The previous element is now the back, so its both field is
+		// just its own predecessor.
+		c.prev = list.node(c.prev).both
+	}
+	return nil
+}
@@ -0,0 +1,320 @@
+package XLL
+
+import (
+	"math/bits"
+	"slices"
+	"sync/atomic"
+)
+
+// Deque is a lock-free double-ended queue for any number of goroutines
+// pushing and popping at both ends, as a work-stealing scheduler needs.
+//
+// Its nodes are linked by XORing slot numbers and allocated in blocks,
+// as in XLL, but the deque has block and node types of its own rather
+// than sharing Block and Node: its both words must be updated atomically
+// and be 64 bits wide on every platform. The state of the ends is an
+// immutable anchor swapped with a single compare-and-swap, following
+// Michael's CAS-based deque: a push installs an anchor that also
+// describes the one both field still to be fixed, and every operation
+// finishes such a fix before doing its own work, so no goroutine ever
+// waits on another.
+//
+// Slots are never reused, so a node's contents do not change while a
+// slow goroutine may still read them. Links are 48 bits wide, enough for
+// 2^48 insertions over the deque's life. A block is dropped once every
+// slot in it has been popped; until then it keeps popped elements
+// reachable.
+type Deque[T any] struct {
+	anchor atomic.Pointer[dequeAnchor]
+	next   atomic.Uint64
+	dir    atomic.Pointer[dequeDir[T]]
+	shift  uint
+}
+
+// A both word carries a tag in its top bits that changes on every fix,
+// so a helper that stalled with an outdated view can never apply a fix a
+// second time. The word is 64 bits even where pointers are narrower, so
+// 32-bit platforms do not run out of links.
+const (
+	dequeTagBits  = 16
+	dequeLinkBits = 64 - dequeTagBits
+	dequeLinkMask = 1<<dequeLinkBits - 1
+)
+
+// dequeAnchor is one immutable state of the ends of a Deque.
+type dequeAnchor struct {
+	head, tail uint64
+	// Popping only moves the anchor, so an end node's both field still
+	// includes the link of the node popped past it, recorded here.
+	headOut, tailOut uint64
+	size             int
+	fix              *dequeFix
+}
+
+// dequeFix swaps the both word of an end node to take in a node pushed
+// beyond it.
+type dequeFix struct {
+	link     uint64
+	old, new uint64
+}
+
+// dequeNode is a node of a Deque: like Node, but with a both word that is
+// always 64 bits and updated atomically.
+type dequeNode[T any] struct {
+	data T
+	both atomic.Uint64
+}
+
+type dequeBlock[T any] struct {
+	nodes  []dequeNode[T]
+	popped atomic.Int64
+}
+
+// dequeDir lists the live blocks of a Deque by block number, in order, so
+// it stays as small as the blocks still in use however long the deque
+// lives. A node is only looked up once its block has been created, so a
+// block missing from the directory has been dropped.
+type dequeDir[T any] struct {
+	nums   []uint64
+	blocks []*dequeBlock[T]
+}
+
+// NewDeque returns an empty lock-free deque. Blocks hold the number of
+// nodes set by WithBlockSize, rounded up to a power of two; other options
+// do not apply.
+func NewDeque[T any](options ...Option[T]) *Deque[T] {
+	d := &Deque[T]{
+		shift: uint(bits.Len(uint(settings(options).blockSize - 1))),
+	}
+	d.anchor.Store(&dequeAnchor{})
+	d.dir.Store(&dequeDir[T]{})
+	return d
+}
+
+// block returns the block holding slot s, creating it on first use if
+// asked to, or nil if it has been dropped.
+func (d *Deque[T]) block(s uint64, create bool) *dequeBlock[T] {
+	n := s >> d.shift
+	var block *dequeBlock[T]
+	for {
+		dir := d.dir.Load()
+		i, found := slices.BinarySearch(dir.nums, n)
+		if found {
+			return dir.blocks[i]
+		}
+		if !create {
+			return nil
+		}
+		if block == nil {
+			block = &dequeBlock[T]{nodes: make([]dequeNode[T], 1<<d.shift)}
+		}
+		next := &dequeDir[T]{
+			nums:   slices.Insert(slices.Clip(dir.nums), i, n),
+			blocks: slices.Insert(slices.Clip(dir.blocks), i, block),
+		}
+		if d.dir.CompareAndSwap(dir, next) {
+			return block
+		}
+	}
+}
+
+// drop removes block number n, whose slots have all been popped.
+func (d *Deque[T]) drop(n uint64) {
+	for {
+		dir := d.dir.Load()
+		i, found := slices.BinarySearch(dir.nums, n)
+		if !found {
+			return
+		}
+		next := &dequeDir[T]{
+			nums:   slices.Concat(dir.nums[:i], dir.nums[i+1:]),
+			blocks: slices.Concat(dir.blocks[:i], dir.blocks[i+1:]),
+		}
+		if d.dir.CompareAndSwap(dir, next) {
+			return
+		}
+	}
+}
+
+// node resolves a link, returning nil if its block has been dropped.
+func (d *Deque[T]) node(link uint64) *dequeNode[T] {
+	s := link - 1
+	block := d.block(s, false)
+	if block == nil {
+		return nil
+	}
+	return &block.nodes[s&(1<<d.shift-1)]
+}
+
+func (d *Deque[T]) alloc(data T) uint64 {
+	s := d.next.Add(1) - 1
+	if s >= dequeLinkMask {
+		panic("XLL: deque slots exhausted")
+	}
+	block := d.block(s, true)
+	block.nodes[s&(1<<d.shift-1)].data = data
+	return s + 1
+}
+
+// stabilize completes the fix described by a, if any, and returns the
+// resulting stable anchor, or nil if another goroutine moved on first.
+func (d *Deque[T]) stabilize(a *dequeAnchor) *dequeAnchor {
+	if a.fix == nil {
+		return a
+	}
+	if node := d.node(a.fix.link); node != nil {
+		node.both.CompareAndSwap(a.fix.old, a.fix.new)
+	}
+	stable := *a
+	stable.fix = nil
+	if d.anchor.CompareAndSwap(a, &stable) {
+		return &stable
+	}
+	return nil
+}
+
+// current returns a stable anchor, helping along any pending fix.
+func (d *Deque[T]) current() *dequeAnchor {
+	for {
+		if a := d.stabilize(d.anchor.Load()); a != nil {
+			return a
+		}
+	}
+}
+
+// InsertFront adds data at the front.
+func (d *Deque[T]) InsertFront(data T) {
+	d.insert(data, true)
+}
+
+// InsertBack adds data at the back.
+func (d *Deque[T]) InsertBack(data T) {
+	d.insert(data, false)
+}
+
+func (d *Deque[T]) insert(data T, front bool) {
+	link := d.alloc(data)
+	node := d.node(link)
+	for {
+		a := d.current()
+		if a.size == 0 {
+			node.both.Store(0)
+			if d.anchor.CompareAndSwap(a, &dequeAnchor{head: link, tail: link, size: 1}) {
+				return
+			}
+			continue
+		}
+
+		b := *a
+		b.size++
+		end, out := a.tail, a.tailOut
+		if front {
+			end, out = a.head, a.headOut
+		}
+		endNode := d.node(end)
+		if endNode == nil {
+			continue
+		}
+		// The anchor is stable, so nothing changes this word until our
+		// own anchor is installed or another one replaces a.
+		old := endNode.both.Load()
+		links := (old & dequeLinkMask) ^ out ^ link
+		b.fix = &dequeFix{link: end, old: old, new: (old>>dequeLinkBits+1)<<dequeLinkBits | links}
+		if front {
+			b.head, b.headOut = link, 0
+		} else {
+			b.tail, b.tailOut = link, 0
+		}
+		node.both.Store(end)
+		if d.anchor.CompareAndSwap(a, &b) {
+			d.stabilize(&b)
+			return
+		}
+	}
+}
+
+// PopFront removes and returns the front element.
+func (d *Deque[T]) PopFront() (T, error) {
+	return d.remove(true)
+}
+
+// PopBack removes and returns the back element.
+func (d *Deque[T]) PopBack() (T, error) {
+	return d.remove(false)
+}
+
+func (d *Deque[T]) remove(front bool) (T, error) {
+	var zero T
+	for {
+		a := d.current()
+		if a.size == 0 {
+			return zero, ErrEmptyList
+		}
+		end, out := a.tail, a.tailOut
+		if front {
+			end, out = a.head, a.headOut
+		}
+		node := d.node(end)
+		if node == nil {
+			continue
+		}
+		data := node.data
+		b := &dequeAnchor{}
+		if a.size > 1 {
+			b = &dequeAnchor{head: a.head, tail: a.tail, headOut: a.headOut, tailOut: a.tailOut, size: a.size - 1}
+			inner := node.both.Load()&dequeLinkMask ^ out
+			if front {
+				b.head, b.headOut = inner, end
+			} else {
+				b.tail, b.tailOut = inner, end
+			}
+		}
+		if d.anchor.CompareAndSwap(a, b) {
+			d.popped(end)
+			return data, nil
+		}
+	}
+}
+
+// popped counts a popped slot and drops its block once all are.
+func (d *Deque[T]) popped(link uint64) {
+	s := link - 1
+	if d.block(s, false).popped.Add(1) == 1<<d.shift {
+		d.drop(s >> d.shift)
+	}
+}
+
+// PeekFront returns the front element without removing it.
+func (d *Deque[T]) PeekFront() (T, error) {
+	return d.peek(true)
+}
+
+// PeekBack returns the back element without removing it.
+func (d *Deque[T]) PeekBack() (T, error) {
+	return d.peek(false)
+}
+
+func (d *Deque[T]) peek(front bool) (T, error) {
+	var zero T
+	for {
+		a := d.anchor.Load()
+		if a.size == 0 {
+			return zero, ErrEmptyList
+		}
+		end := a.tail
+		if front {
+			end = a.head
+		}
+		// A node is dropped only after it has been popped, so a missing
+		// block means a is already out of date.
+		if node := d.node(end); node != nil {
+			return node.data, nil
+		}
+	}
+}
+
+// Size returns the number of elements, which may be out of date as soon
+// as it returns.
+func (d *Deque[T]) Size() int {
+	return d.anchor.Load().size
+}
@@ -0,0 +1,270 @@
+package XLL
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDeque(t *testing.T) {
+	d := NewDeque[int](WithBlockSize[int](4))
+	rng := rand.New(rand.NewPCG(3, 4))
+	var expected []int
+
+	for i := 0; i < 5000; i++ {
+		switch rng.IntN(4) {
+		case 0:
+			d.InsertFront(i)
+			expected = append([]int{i}, expected...)
+		case 1:
+			d.InsertBack(i)
+			expected = append(expected, i)
+		case 2:
+			v, err := d.PopFront()
+			if len(expected) == 0 {
+				if !errors.Is(err, ErrEmptyList) {
+					t.Fatalf("Expected ErrEmptyList, got %v", err)
+				}
+				continue
+			}
+			if err != nil || v != expected[0] {
+				t.Fatalf("Expected PopFront to return %d, got %d (%v)", expected[0], v, err)
+			}
+			expected = expected[1:]
+		default:
+			v, err := d.PopBack()
+			if len(expected) == 0 {
+				if !errors.Is(err, ErrEmptyList) {
+					t.Fatalf("Expected ErrEmptyList, got %v", err)
+				}
+				continue
+			}
+			if err != nil || v != expected[len(expected)-1] {
+				t.Fatalf("Expected PopBack to return %d, got %d (%v)", expected[len(expected)-1], v, err)
+			}
+			expected = expected[:len(expected)-1]
+		}
+		if d.Size() != len(expected) {
+			t.Fatalf("Expected size %d, got %d", len(expected), d.Size())
+		}
+		if len(expected) > 0 {
+			if v, err := d.PeekFront(); err != nil || v != expected[0] {
+				t.Fatalf("Expected PeekFront to return %d, got %d (%v)", expected[0], v, err)
+			}
+			if v, err := d.PeekBack(); err != nil || v != expected[len(expected)-1] {
+				t.Fatalf("Expected PeekBack to return %d, got %d (%v)", expected[len(expected)-1], v, err)
+			}
+		}
+	}
+
+	// Test fully popped blocks are dropped
+	for d.Size() > 0 {
+		if _, err := d.PopFront(); err != nil {
+			t.Fatalf("PopFront failed: %v", err)
+		}
+	}
+	if n := len(d.dir.Load().blocks); n > 1 {
+		t.Errorf("Expected popped blocks to be dropped, %d remain", n)
+	}
+
+	// Test blocks behind an element that stays are dropped too
+	d.InsertBack(-1)
+	for i := 0; i < 100000; i++ {
+		d.InsertBack(i)
+		if _, err := d.PopBack(); err != nil {
+			t.Fatalf("PopBack failed: %v", err)
+		}
+	}
+	if n := len(d.dir.Load().blocks); n > 3 {
+		t.Errorf("Expected the directory to stay small, it has %d blocks", n)
+	}
+	if v, err := d.PopFront(); err != nil || v != -1 {
+		t.Errorf("Expected PopFront to return -1, got %d (%v)", v, err)
+	}
+
+	// Test links past 32 bits, which a long-lived deque reaches on every
+	// platform
+	d = NewDeque[int](WithBlockSize[int](4))
+	d.next.Store(1<<40 - 2)
+	for i := 1; i <= 3; i++ {
+		d.InsertBack(i)
+	}
+	d.InsertFront(0)
+	for i := 0; i <= 3; i++ {
+		if v, err := d.PopFront(); err != nil || v != i {
+			t.Errorf("Expected PopFront to return %d, got %d (%v)", i, v, err)
+		}
+	}
+}
+
+func TestDequeConcurrent(t *testing.T) {
+	d := NewDeque[int](WithBlockSize[int](64))
+	producers, consumers, n := 4, 4, 5000
+	var wg sync.WaitGroup
+	var remaining atomic.Int64
+	remaining.Store(int64(producers * n))
+	seen := make([][]int, consumers)
+
+	// Producers push at the back, consumers pop at the front, so each
+	// consumer must see each producer's elements in order.
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				d.InsertBack(p*n + i)
+			}
+		}(p)
+	}
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			last := make([]int, producers)
+			for i := range last {
+				last[i] = -1
+			}
+			for remaining.Load() > 0 {
+				v, err := d.PopFront()
+				if err != nil {
+					continue
+				}
+				remaining.Add(-1)
+				if p := v / n; v <= last[p] {
+					t.Errorf("Consumer %d saw %d after %d", c, v, last[p])
+				} else {
+					last[p] = v
+				}
+				seen[c] = append(seen[c], v)
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	// Test every element came out exactly once
+	all := slices.Concat(seen...)
+	slices.Sort(all)
+	for i, v := range all {
+		if v != i {
+			t.Fatalf("Expected element %d, got %d", i, v)
+		}
+	}
+	if len(all) != producers*n || d.Size() != 0 {
+		t.Errorf("Expected %d elements and an empty deque, got %d and size %d", producers*n, len(all), d.Size())
+	}
+}
+
+// dequeOp is one operation in a recorded history. call and ret are ticks
+// of a shared clock taken before and after the operation ran.
+type dequeOp struct {
+	kind      int // 0 InsertFront, 1 InsertBack, 2 PopFront, 3 PopBack
+	value     int
+	ok        bool
+	call, ret int64
+}
+
+func TestDequeLinearizable(t *testing.T) {
+	rng := rand.New(rand.NewPCG(5, 6))
+	for round := 0; round < 300; round++ {
+		d := NewDeque[int](WithBlockSize[int](2))
+		initial := []int{-1, -2}
+		for _, v := range initial {
+			d.InsertBack(v)
+		}
+
+		var clock atomic.Int64
+		var start, wg sync.WaitGroup
+		start.Add(1)
+		workers, perWorker := 3, 4
+		history := make([]dequeOp, workers*perWorker)
+		for i := range history {
+			history[i] = dequeOp{kind: rng.IntN(4), value: i}
+		}
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(ops []dequeOp) {
+				defer wg.Done()
+				start.Wait()
+				for i := range ops {
+					op := &ops[i]
+					op.call = clock.Add(1)
+					var err error
+					switch op.kind {
+					case 0:
+						d.InsertFront(op.value)
+					case 1:
+						d.InsertBack(op.value)
+					case 2:
+						op.value, err = d.PopFront()
+					default:
+						op.value, err = d.PopBack()
+					}
+					op.ok = err == nil
+					op.ret = clock.Add(1)
+				}
+			}(history[w*perWorker : (w+1)*perWorker])
+		}
+		start.Done()
+		wg.Wait()
+
+		if !linearizable(history, 0, initial) {
+			t.Fatalf("History is not linearizable: %+v", history)
+		}
+	}
+}
+
+// linearizable searches for an order of the operations not yet in done
+// that respects their real-time order and matches a sequential deque
+// starting out as state.
+func linearizable(history []dequeOp, done uint, state []int) bool {
+	if done == 1<<len(history)-1 {
+		return true
+	}
+	minRet := int64(-1)
+	for i, op := range history {
+		if done&(1<<i) == 0 && (minRet < 0 || op.ret < minRet) {
+			minRet = op.ret
+		}
+	}
+	for i, op := range history {
+		// An operation can go next only if no other pending operation
+		// finished before it started.
+		if done&(1<<i) != 0 || op.call > minRet {
+			continue
+		}
+		next := slices.Clone(state)
+		switch op.kind {
+		case 0:
+			next = append([]int{op.value}, next...)
+		case 1:
+			next = append(next, op.value)
+		case 2:
+			if len(next) == 0 {
+				if op.ok {
+					continue
+				}
+			} else if !op.ok || next[0] != op.value {
+				continue
+			} else {
+				next = next[1:]
+			}
+		default:
+			if len(next) == 0 {
+				if op.ok {
+					continue
+				}
+			} else if !op.ok || next[len(next)-1] != op.value {
+				continue
+			} else {
+				next = next[:len(next)-1]
+			}
+		}
+		if linearizable(history, done|1<<i, next) {
+			return true
+		}
+	}
+	return false
+}
@@ -58,6 +58,13 @@ func main() {
 	}
 	fmt.Println()
 
+	// Range over the list
+	fmt.Println("Using range:")
+	for i, v := range list.Enumerate() {
+		fmt.Printf("%d:%v ", i, v)
+	}
+	fmt.Println()
+
 	// Free the list
 	if err := list.Free(); err != nil {
 		fmt.Printf("Error freeing list: %v\n", err)
@@ -0,0 +1,226 @@
+package XLL
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+// A file-backed list keeps its index mode arena in a memory-mapped file:
+// a header recording the list's state, followed by the nodes. Links are
+// slot numbers, so they name positions in the file rather than addresses
+// and stay valid when the file is mapped again. The file uses the
+// machine's own byte order and layout of Node[T].
+
+const (
+	fileVersion    = 1
+	fileHeaderSize = 64
+)
+
+var fileMagic = [4]byte{'X', 'L', 'L', 'F'}
+
+type fileHeader struct {
+	Magic    [4]byte
+	Version  uint32
+	NodeSize uint64
+	Head     uint64
+	Tail     uint64
+	Size     uint64
+	Len      uint64 // slots ever handed out in the arena
+	Free     uint64 // index+1 of the first dead slot, 0 if none
+}
+
+// mappedFile is the file behind a file-backed list and its mapping.
+type mappedFile struct {
+	f    *os.File
+	data []byte
+}
+
+// OpenFile opens the list stored in the file at path, creating an empty
+// one if the file does not exist or is empty. T must not contain
+// pointers, strings, slices, maps or interfaces, as their targets would
+// not be in the file; other types return ErrPointerElements.
+//
+// The list is in index mode, and its arena grows by remapping the file.
+// Changes reach the mapping at once, but the file is only consistent as
+// of the last Sync, Close or Free; a crash in between can leave the header
+// out of step with the nodes. If the file cannot be grown when the list
+// needs room, the insertion returns the error and leaves the list as it
+// was.
+func OpenFile[T any](path string, options ...Option[T]) (*XLL[T], error) {
+	var zero T
+	if !pointerFree(reflect.TypeFor[T]()) {
+		return nil, fmt.Errorf("%w: %T", ErrPointerElements, zero)
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	list, err := mapList(f, options)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	runtime.SetFinalizer(list, (*XLL[T]).Free)
+	return list, nil
+}
+
+// mapList maps f and builds a list over it.
+func mapList[T any](f *os.File, options []Option[T]) (*XLL[T], error) {
+	list := settings(options)
+	list.indexed = true
+	nodeSize := int64(unsafe.Sizeof(Node[T]{}))
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size == 0 {
+		size = fileHeaderSize + int64(list.blockSize)*nodeSize
+		if err := f.Truncate(size); err != nil {
+			return nil, err
+		}
+	}
+	if size < fileHeaderSize || (size-fileHeaderSize)%nodeSize != 0 {
+		return nil, fmt.Errorf("%w: bad file size %d", ErrCorrupt, size)
+	}
+	data, err := mmap(f, int(size))
+	if err != nil {
+		return nil, err
+	}
+	list.file = &mappedFile{f: f, data: data}
+
+	capacity := int((size - fileHeaderSize) / nodeSize)
+	header := list.file.header()
+	if header.Magic == [4]byte{} {
+		*header = fileHeader{Magic: fileMagic, Version: fileVersion, NodeSize: uint64(nodeSize)}
+	}
+	switch {
+	case header.Magic != fileMagic:
+		err = fmt.Errorf("%w: bad magic %q", ErrCorrupt, header.Magic[:])
+	case header.Version != fileVersion:
+		err = fmt.Errorf("%w: unsupported version %d", ErrCorrupt, header.Version)
+	case header.NodeSize != uint64(nodeSize):
+		err = fmt.Errorf("%w: nodes of %d bytes, want %d", ErrCorrupt, header.NodeSize, nodeSize)
+	case header.Len > uint64(capacity), header.Size > header.Len, header.Head > header.Len,
+		header.Tail > header.Len, header.Free > header.Len, (header.Head == 0) != (header.Size == 0):
+		err = fmt.Errorf("%w: bad header", ErrCorrupt)
+	}
+	if err != nil {
+		munmap(data)
+		return nil, err
+	}
+
+	list.blocks = &Block[T]{
+		nodes: fileNodes[T](data, capacity)[:header.Len],
+		live:  int(header.Size),
+		free:  int(header.Free),
+	}
+	list.capacity = capacity
+	list.head = uintptr(header.Head)
+	list.tail = uintptr(header.Tail)
+	list.size = int(header.Size)
+	return list, nil
+}
+
+// pointerFree reports whether values of type t hold no pointers.
+func pointerFree(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return t.Len() == 0 || pointerFree(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !pointerFree(t.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func (m *mappedFile) header() *fileHeader {
+	return (*fileHeader)(unsafe.Pointer(unsafe.SliceData(m.data)))
+}
+
+// fileNodes returns the capacity nodes following the header in data.
+func fileNodes[T any](data []byte, capacity int) []Node[T] {
+	return unsafe.Slice((*Node[T])(unsafe.Pointer(&data[fileHeaderSize])), capacity)
+}
+
+// growFile remaps the arena of a file-backed list onto a file large
+// enough for capacity nodes. On failure the list keeps its old mapping.
+// The caller must hold list.mu.
+func (list *XLL[T]) growFile(capacity int) error {
+	m, arena := list.file, list.blocks
+	size := fileHeaderSize + int64(capacity)*int64(unsafe.Sizeof(Node[T]{}))
+	if err := m.f.Truncate(size); err != nil {
+		return fmt.Errorf("growing mapped file: %w", err)
+	}
+	data, err := mmap(m.f, int(size))
+	if err != nil {
+		return fmt.Errorf("growing mapped file: %w", err)
+	}
+	munmap(m.data)
+	m.data = data
+	arena.nodes = fileNodes[T](data, capacity)[:len(arena.nodes)]
+	list.capacity = capacity
+	return nil
+}
+
+// Sync writes a file-backed list's state and nodes to its file. It does
+// nothing for other lists.
+func (list *XLL[T]) Sync() error {
+	list.lock()
+	defer list.unlock()
+	if list.IsFreed() {
+		return ErrFreedList
+	}
+	if list.file == nil {
+		return nil
+	}
+	return list.syncFile()
+}
+
+// Close syncs a file-backed list, unmaps it and closes its file. Like
+// Free, which does the same, it leaves the list freed; for other lists it
+// is Free.
+func (list *XLL[T]) Close() error {
+	return list.Free()
+}
+
+// syncFile records the list's state in the header and flushes the
+// mapping. The caller must hold list.mu.
+func (list *XLL[T]) syncFile() error {
+	header := list.file.header()
+	header.Head = uint64(list.head)
+	header.Tail = uint64(list.tail)
+	header.Size = uint64(list.size)
+	header.Len = uint64(len(list.blocks.nodes))
+	header.Free = uint64(list.blocks.free)
+	return msync(list.file.data)
+}
+
+// closeFile syncs and unmaps the list's file and detaches the list from
+// it, leaving it empty. The caller must hold list.mu.
+func (list *XLL[T]) closeFile() error {
+	m := list.file
+	err := list.syncFile()
+	list.file = nil
+	list.head, list.tail, list.size = 0, 0, 0
+	list.blocks, list.capacity = nil, 0
+	if e := munmap(m.data); err == nil {
+		err = e
+	}
+	if e := m.f.Close(); err == nil {
+		err = e
+	}
+	return err
+}
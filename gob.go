@@ -0,0 +1,54 @@
+package XLL
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+)
+
+// gobList is the form in which gob sees a list. Elements are encoded by
+// gob itself, so unlike MarshalBinary no codec is needed; pointers are
+// followed, which means elements must not be nil pointers.
+type gobList[T any] struct {
+	BlockSize  int
+	GrowthRate float64
+	Items      []T
+}
+
+// GobEncode encodes the list's elements, block size and growth rate for
+// encoding/gob. It takes precedence over MarshalBinary there.
+func (list *XLL[T]) GobEncode() ([]byte, error) {
+	list.rlock()
+	if list.IsFreed() {
+		list.runlock()
+		return nil, ErrFreedList
+	}
+	g := gobList[T]{BlockSize: list.blockSize, GrowthRate: list.growthRate, Items: list.elements()}
+	list.runlock()
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&g); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode replaces the list's contents with those encoded by GobEncode,
+// taking on the encoded block size and growth rate. Like ReadFrom it works
+// on the zero value of XLL.
+func (list *XLL[T]) GobDecode(data []byte) error {
+	var g gobList[T]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&g); err != nil {
+		return err
+	}
+	if g.BlockSize <= 0 || !(g.GrowthRate > 1) {
+		return fmt.Errorf("%w: bad block size %d or growth rate %v", ErrCorrupt, g.BlockSize, g.GrowthRate)
+	}
+
+	list.lock()
+	defer list.unlock()
+	if list.IsFreed() {
+		return ErrFreedList
+	}
+	return list.replace(g.Items, g.BlockSize, g.GrowthRate)
+}
@@ -0,0 +1,38 @@
+//go:build xlldebug
+
+package XLL
+
+import "sync/atomic"
+
+// guard checks that a list built WithoutLocking is not used by several
+// goroutines at once. It counts readers, or holds -1 while a writer is
+// inside, and panics on any overlap a mutex would have had to wait for.
+type guard struct {
+	state atomic.Int32
+}
+
+func (g *guard) enter() {
+	if !g.state.CompareAndSwap(0, -1) {
+		panic("XLL: concurrent use of a list built WithoutLocking")
+	}
+}
+
+func (g *guard) leave() {
+	g.state.Store(0)
+}
+
+func (g *guard) renter() {
+	for {
+		n := g.state.Load()
+		if n < 0 {
+			panic("XLL: concurrent use of a list built WithoutLocking")
+		}
+		if g.state.CompareAndSwap(n, n+1) {
+			return
+		}
+	}
+}
+
+func (g *guard) rleave() {
+	g.state.Add(-1)
+}
@@ -0,0 +1,47 @@
+//go:build xlldebug
+
+package XLL
+
+import "testing"
+
+// Test overlapping use of a list built WithoutLocking panics
+func TestGuard(t *testing.T) {
+	expectPanic := func(name string, f func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expected %s to panic", name)
+			}
+		}()
+		f()
+	}
+
+	list := New[int](WithoutLocking[int]())
+	_ = list.InsertBackAll(1, 2, 3)
+
+	expectPanic("inserting while ranging", func() {
+		for range list.All() {
+			_ = list.InsertBack(4)
+		}
+	})
+
+	list = New[int](WithoutLocking[int]())
+	_ = list.InsertBackAll(1, 2, 3)
+	expectPanic("traversing while removing", func() {
+		_ = list.TraverseForward(func(int) {
+			_ = list.DeleteFront()
+		})
+	})
+
+	// Nested reads are fine
+	list = New[int](WithoutLocking[int]())
+	_ = list.InsertBackAll(1, 2, 3)
+	for range list.All() {
+		if list.Size() != 3 {
+			t.Errorf("Expected size 3, got %d", list.Size())
+		}
+	}
+	if err := list.InsertBack(4); err != nil {
+		t.Errorf("Expected the guard to be released, got %v", err)
+	}
+}
@@ -7,8 +7,8 @@ func XOR(a, b uintptr) uintptr {
 }
 
 func (list *XLL[T]) Size() int {
-	list.mu.RLock()
-	defer list.mu.RUnlock()
+	list.rlock()
+	defer list.runlock()
 	return list.size
 }
 
@@ -1,39 +1,89 @@
 package XLL
 
-import "unsafe"
+import "iter"
 
 // Iterator represents an iterator over the XOR linked list elements.
 type Iterator[T any] struct {
-	current *Node[T]
+	current uintptr
 	prev    uintptr
+	started bool
 	list    *XLL[T]
 }
 
 // Next advances the iterator and returns whether there is a next element.
+// The first call moves to the front element.
 func (it *Iterator[T]) Next() bool {
-	if it.current == nil {
+	if !it.started {
+		it.started = true
+		it.current = it.list.head
+		return it.current != 0
+	}
+	if it.current == 0 {
 		return false
 	}
-	next := XOR(it.prev, it.current.both)
-	it.prev = uintptr(unsafe.Pointer(it.current))
-	it.current = (*Node[T])(unsafe.Pointer(next))
-	return it.current != nil
+	it.prev, it.current = it.current, XOR(it.prev, it.list.node(it.current).both)
+	return it.current != 0
 }
 
 // Value returns the current value of the iterator.
 // It panics if called when there is no current value.
 func (it *Iterator[T]) Value() T {
-	if it.current == nil {
+	if it.current == 0 {
 		panic("Value called on exhausted iterator")
 	}
-	return it.current.data
+	return it.list.node(it.current).data
 }
 
 // Iterator returns an iterator for the list that can be used with range.
 func (list *XLL[T]) Iterator() *Iterator[T] {
-	return &Iterator[T]{
-		current: list.head,
-		prev:    0,
-		list:    list,
+	return &Iterator[T]{list: list}
+}
+
+// All returns an iterator over the elements from front to back, for use
+// with range. The read lock is held until the loop ends, so the loop body
+// must not modify the list. Ranging over a freed list panics with
+// ErrFreedList.
+func (list *XLL[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		list.walk(true, func(_ int, data T) bool {
+			return yield(data)
+		})
+	}
+}
+
+// Backward is like All but runs from back to front.
+func (list *XLL[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		list.walk(false, func(_ int, data T) bool {
+			return yield(data)
+		})
+	}
+}
+
+// Enumerate is like All but also yields each element's position,
+// counting from zero at the front.
+func (list *XLL[T]) Enumerate() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		list.walk(true, yield)
+	}
+}
+
+func (list *XLL[T]) walk(forward bool, yield func(int, T) bool) {
+	list.rlock()
+	defer list.runlock()
+	if list.IsFreed() {
+		panic(ErrFreedList)
+	}
+	var prev uintptr
+	curr := list.tail
+	if forward {
+		curr = list.head
+	}
+	for i := 0; curr != 0; i++ {
+		node := list.node(curr)
+		if !yield(i, node.data) {
+			return
+		}
+		prev, curr = curr, XOR(prev, node.both)
 	}
 }
@@ -0,0 +1,119 @@
+package XLL
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// MarshalJSON encodes the list as a JSON array of its elements from front
+// to back.
+func (list *XLL[T]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := list.WriteJSON(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON replaces the list's contents with the elements of a JSON
+// array, as ReadJSON does. JSON null leaves the list unchanged. Anything
+// but white space after the value is an error.
+func (list *XLL[T]) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	values, null, err := decodeJSON[T](dec)
+	if err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return fmt.Errorf("%w: trailing data after JSON value", ErrCorrupt)
+	}
+	if null {
+		return nil
+	}
+	return list.setJSON(values)
+}
+
+// WriteJSON streams the list to w as a JSON array, one element at a time.
+func (list *XLL[T]) WriteJSON(w io.Writer) error {
+	list.rlock()
+	defer list.runlock()
+	if list.IsFreed() {
+		return ErrFreedList
+	}
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	var prev uintptr
+	for curr := list.head; curr != 0; {
+		if curr != list.head {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		node := list.node(curr)
+		if err := enc.Encode(node.data); err != nil {
+			return err
+		}
+		prev, curr = curr, XOR(prev, node.both)
+	}
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+// ReadJSON reads a JSON array from r and replaces the list's contents with
+// its elements, keeping the list's options. Elements are decoded one at a
+// time, and the list is only changed once the whole array has been read.
+// r may be read beyond the end of the array.
+// It works on the zero value of XLL too, which gets the default block
+// size and growth rate.
+func (list *XLL[T]) ReadJSON(r io.Reader) error {
+	values, null, err := decodeJSON[T](json.NewDecoder(r))
+	if err != nil || null {
+		return err
+	}
+	return list.setJSON(values)
+}
+
+// decodeJSON reads a JSON array of elements from dec, reporting whether it
+// read null instead.
+func decodeJSON[T any](dec *json.Decoder) ([]T, bool, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, false, err
+	}
+	if tok == nil {
+		return nil, true, nil
+	}
+	if tok != json.Delim('[') {
+		return nil, false, fmt.Errorf("%w: expected a JSON array, got %v", ErrCorrupt, tok)
+	}
+	var values []T
+	for dec.More() {
+		var v T
+		if err := dec.Decode(&v); err != nil {
+			return nil, false, fmt.Errorf("decoding element %d: %w", len(values), err)
+		}
+		values = append(values, v)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, false, err
+	}
+	return values, false, nil
+}
+
+// setJSON replaces the list's contents with values decoded from JSON.
+func (list *XLL[T]) setJSON(values []T) error {
+	list.lock()
+	defer list.unlock()
+	if list.IsFreed() {
+		return ErrFreedList
+	}
+	blockSize, growthRate := list.blockSize, list.growthRate
+	if blockSize == 0 {
+		blockSize, growthRate = defaultBlockSize, defaultGrowthRate
+	}
+	return list.replace(values, blockSize, growthRate)
+}
@@ -0,0 +1,46 @@
+package XLL
+
+// WithoutLocking makes the list skip its mutex. Every method must then be
+// called from one goroutine at a time; the list does no synchronisation
+// of its own. Building with the xlldebug tag makes overlapping calls
+// panic instead of corrupting the list silently.
+func WithoutLocking[T any]() Option[T] {
+	return func(list *XLL[T]) {
+		list.unsync = true
+	}
+}
+
+func (list *XLL[T]) lock() {
+	if list.unsync {
+		list.guard.enter()
+		return
+	}
+	list.mu.Lock()
+}
+
+func (list *XLL[T]) unlock() {
+	if list.wal != nil {
+		list.flushWAL()
+	}
+	if list.unsync {
+		list.guard.leave()
+		return
+	}
+	list.mu.Unlock()
+}
+
+func (list *XLL[T]) rlock() {
+	if list.unsync {
+		list.guard.renter()
+		return
+	}
+	list.mu.RLock()
+}
+
+func (list *XLL[T]) runlock() {
+	if list.unsync {
+		list.guard.rleave()
+		return
+	}
+	list.mu.RUnlock()
+}
@@ -0,0 +1,237 @@
+package XLL
+
+import "iter"
+
+// Persistent is an immutable list. Operations that change it return a
+// new list and leave the old one valid and unchanged, so keeping earlier
+// versions around, as an undo stack does, costs only the parts that
+// differ.
+//
+// An XOR link depends on both neighbours of a node, so two versions could
+// never share a node whose neighbours differ between them. Persistent
+// therefore keeps its elements in a balanced tree ordered by position:
+// each change copies only the O(log n) nodes on the path to the end it
+// touches and shares every other node with the version it came from.
+//
+// The zero value is an empty, unbounded list. Persistent values are safe
+// to use from several goroutines at once.
+type Persistent[T any] struct {
+	root    *pnode[T]
+	maxSize int
+	policy  OverflowPolicy
+}
+
+// pnode is a node of the tree behind a Persistent list. It is never
+// modified once built.
+type pnode[T any] struct {
+	left, right *pnode[T]
+	data        T
+	size        int
+	height      int
+}
+
+// NewPersistent returns an empty persistent list. Only WithMaxSize
+// applies: a bounded list handles pushes onto a full list as its overflow
+// policy says, which keeps an undo history from growing forever.
+func NewPersistent[T any](options ...Option[T]) Persistent[T] {
+	config := settings(options)
+	return Persistent[T]{maxSize: config.maxSize, policy: config.policy}
+}
+
+// Size returns the number of elements.
+func (p Persistent[T]) Size() int {
+	return p.root.len()
+}
+
+// PushFront returns the list with data added at the front.
+func (p Persistent[T]) PushFront(data T) (Persistent[T], error) {
+	return p.push(data, true)
+}
+
+// PushBack returns the list with data added at the back.
+func (p Persistent[T]) PushBack(data T) (Persistent[T], error) {
+	return p.push(data, false)
+}
+
+func (p Persistent[T]) push(data T, front bool) (Persistent[T], error) {
+	if p.maxSize > 0 && p.Size() >= p.maxSize {
+		switch p.policy {
+		case OverflowDropOldest:
+			_, p.root = p.root.pop(!front)
+		case OverflowDropNewest:
+			return p, nil
+		default:
+			return p, ErrFullList
+		}
+	}
+	p.root = p.root.push(data, front)
+	return p, nil
+}
+
+// PopFront returns the front element and the list without it.
+func (p Persistent[T]) PopFront() (T, Persistent[T], error) {
+	return p.remove(true)
+}
+
+// PopBack returns the back element and the list without it.
+func (p Persistent[T]) PopBack() (T, Persistent[T], error) {
+	return p.remove(false)
+}
+
+func (p Persistent[T]) remove(front bool) (T, Persistent[T], error) {
+	if p.root == nil {
+		var zero T
+		return zero, p, ErrEmptyList
+	}
+	var data T
+	data, p.root = p.root.pop(front)
+	return data, p, nil
+}
+
+// PeekFront returns the front element.
+func (p Persistent[T]) PeekFront() (T, error) {
+	return p.peek(0)
+}
+
+// PeekBack returns the back element.
+func (p Persistent[T]) PeekBack() (T, error) {
+	return p.peek(p.Size() - 1)
+}
+
+func (p Persistent[T]) peek(i int) (T, error) {
+	if p.root == nil {
+		var zero T
+		return zero, ErrEmptyList
+	}
+	return p.At(i)
+}
+
+// At returns the element at position i in O(log n).
+func (p Persistent[T]) At(i int) (T, error) {
+	if i < 0 || i >= p.Size() {
+		var zero T
+		return zero, &IndexError{Index: i, Size: p.Size()}
+	}
+	n := p.root
+	for {
+		switch left := n.left.len(); {
+		case i < left:
+			n = n.left
+		case i == left:
+			return n.data, nil
+		default:
+			i -= left + 1
+			n = n.right
+		}
+	}
+}
+
+// All returns an iterator over the elements from front to back.
+func (p Persistent[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		p.root.walk(true, yield)
+	}
+}
+
+// Backward returns an iterator over the elements from back to front.
+func (p Persistent[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		p.root.walk(false, yield)
+	}
+}
+
+// ToSlice returns the elements from front to back.
+func (p Persistent[T]) ToSlice() []T {
+	s := make([]T, 0, p.Size())
+	for data := range p.All() {
+		s = append(s, data)
+	}
+	return s
+}
+
+func (n *pnode[T]) len() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *pnode[T]) depth() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// pmake builds a node over two subtrees whose heights differ by at most
+// one.
+func pmake[T any](left *pnode[T], data T, right *pnode[T]) *pnode[T] {
+	return &pnode[T]{
+		left:   left,
+		right:  right,
+		data:   data,
+		size:   left.len() + 1 + right.len(),
+		height: max(left.depth(), right.depth()) + 1,
+	}
+}
+
+// pbalance builds a node over two subtrees whose heights differ by at most
+// two, rotating to keep the result balanced.
+func pbalance[T any](left *pnode[T], data T, right *pnode[T]) *pnode[T] {
+	switch {
+	case left.depth() > right.depth()+1:
+		if left.left.depth() >= left.right.depth() {
+			return pmake(left.left, left.data, pmake(left.right, data, right))
+		}
+		inner := left.right
+		return pmake(pmake(left.left, left.data, inner.left), inner.data, pmake(inner.right, data, right))
+	case right.depth() > left.depth()+1:
+		if right.right.depth() >= right.left.depth() {
+			return pmake(pmake(left, data, right.left), right.data, right.right)
+		}
+		inner := right.left
+		return pmake(pmake(left, data, inner.left), inner.data, pmake(inner.right, right.data, right.right))
+	}
+	return pmake(left, data, right)
+}
+
+// push returns the tree with data added at one end.
+func (n *pnode[T]) push(data T, front bool) *pnode[T] {
+	if n == nil {
+		return pmake(nil, data, nil)
+	}
+	if front {
+		return pbalance(n.left.push(data, true), n.data, n.right)
+	}
+	return pbalance(n.left, n.data, n.right.push(data, false))
+}
+
+// pop returns the element at one end of a non-empty tree and the tree
+// without it.
+func (n *pnode[T]) pop(front bool) (T, *pnode[T]) {
+	if front {
+		if n.left == nil {
+			return n.data, n.right
+		}
+		data, left := n.left.pop(true)
+		return data, pbalance(left, n.data, n.right)
+	}
+	if n.right == nil {
+		return n.data, n.left
+	}
+	data, right := n.right.pop(false)
+	return data, pbalance(n.left, n.data, right)
+}
+
+// walk yields the elements of the tree in order or in reverse, reporting
+// whether to go on.
+func (n *pnode[T]) walk(forward bool, yield func(T) bool) bool {
+	if n == nil {
+		return true
+	}
+	first, second := n.left, n.right
+	if !forward {
+		first, second = second, first
+	}
+	return first.walk(forward, yield) && yield(n.data) && second.walk(forward, yield)
+}
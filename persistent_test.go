@@ -0,0 +1,140 @@
+package XLL
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestPersistent(t *testing.T) {
+	var empty Persistent[int]
+	if _, _, err := empty.PopFront(); !errors.Is(err, ErrEmptyList) {
+		t.Errorf("Expected ErrEmptyList, got %v", err)
+	}
+	if _, err := empty.PeekBack(); !errors.Is(err, ErrEmptyList) {
+		t.Errorf("Expected ErrEmptyList, got %v", err)
+	}
+
+	// Test every version keeps its elements while later ones are built
+	// from random earlier ones
+	rng := rand.New(rand.NewPCG(7, 8))
+	versions := []Persistent[int]{NewPersistent[int]()}
+	expected := [][]int{nil}
+	for i := 0; i < 3000; i++ {
+		// Mostly build on the latest version, sometimes branch off an
+		// older one
+		k := len(versions) - 1
+		if rng.IntN(10) == 0 {
+			k = rng.IntN(len(versions))
+		}
+		p, want := versions[k], slices.Clone(expected[k])
+		var err error
+		switch op := rng.IntN(5); {
+		case op == 0:
+			p, err = p.PushFront(i)
+			want = append([]int{i}, want...)
+		case op <= 2:
+			p, err = p.PushBack(i)
+			want = append(want, i)
+		case len(want) == 0:
+			continue
+		case op == 3:
+			var v int
+			v, p, err = p.PopFront()
+			if v != want[0] {
+				t.Fatalf("Expected PopFront to return %d, got %d", want[0], v)
+			}
+			want = want[1:]
+		default:
+			var v int
+			v, p, err = p.PopBack()
+			if v != want[len(want)-1] {
+				t.Fatalf("Expected PopBack to return %d, got %d", want[len(want)-1], v)
+			}
+			want = want[:len(want)-1]
+		}
+		if err != nil {
+			t.Fatalf("Operation failed: %v", err)
+		}
+		versions = append(versions, p)
+		expected = append(expected, want)
+	}
+	for k, p := range versions {
+		checkPersistent(t, p, expected[k])
+	}
+}
+
+// Test the size bound and its overflow policies
+func TestPersistentBounded(t *testing.T) {
+	p := NewPersistent[int](WithMaxSize[int](3, OverflowDropOldest))
+	for i := 1; i <= 5; i++ {
+		p, _ = p.PushBack(i)
+	}
+	checkPersistent(t, p, []int{3, 4, 5})
+	old := p
+	p, _ = p.PushFront(2)
+	checkPersistent(t, p, []int{2, 3, 4})
+	checkPersistent(t, old, []int{3, 4, 5})
+
+	p = NewPersistent[int](WithMaxSize[int](2, OverflowDropNewest))
+	p, _ = p.PushBack(1)
+	p, _ = p.PushBack(2)
+	p, _ = p.PushBack(3)
+	checkPersistent(t, p, []int{1, 2})
+
+	p = NewPersistent[int](WithMaxSize[int](1, OverflowReject))
+	p, _ = p.PushBack(1)
+	if _, err := p.PushBack(2); !errors.Is(err, ErrFullList) {
+		t.Errorf("Expected ErrFullList, got %v", err)
+	}
+	_, p, _ = p.PopFront()
+	if _, err := p.PushBack(2); err != nil {
+		t.Errorf("PushBack failed: %v", err)
+	}
+}
+
+// checkPersistent checks a version's contents through every accessor and
+// that its tree is balanced.
+func checkPersistent(t *testing.T, p Persistent[int], expected []int) {
+	t.Helper()
+	if p.Size() != len(expected) {
+		t.Fatalf("Expected size %d, got %d", len(expected), p.Size())
+	}
+	if got := p.ToSlice(); !slices.Equal(got, expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	backward := slices.Collect(p.Backward())
+	slices.Reverse(backward)
+	if !slices.Equal(backward, expected) {
+		t.Fatalf("Expected %v backwards, got %v", expected, backward)
+	}
+	for i, want := range expected {
+		if v, err := p.At(i); err != nil || v != want {
+			t.Fatalf("Expected At(%d) to return %d, got %d (%v)", i, want, v, err)
+		}
+	}
+	var idxErr *IndexError
+	if _, err := p.At(len(expected)); !errors.As(err, &idxErr) {
+		t.Errorf("Expected an IndexError, got %v", err)
+	}
+	if len(expected) > 0 {
+		front, _ := p.PeekFront()
+		back, _ := p.PeekBack()
+		if front != expected[0] || back != expected[len(expected)-1] {
+			t.Errorf("Expected ends %d and %d, got %d and %d", expected[0], expected[len(expected)-1], front, back)
+		}
+	}
+	var balanced func(n *pnode[int]) bool
+	balanced = func(n *pnode[int]) bool {
+		if n == nil {
+			return true
+		}
+		l, r := n.left.depth(), n.right.depth()
+		return n.height == max(l, r)+1 && l-r <= 1 && r-l <= 1 &&
+			n.size == n.left.len()+1+n.right.len() && balanced(n.left) && balanced(n.right)
+	}
+	if !balanced(p.root) {
+		t.Errorf("Expected a balanced tree")
+	}
+}
@@ -0,0 +1,121 @@
+package XLL
+
+import "fmt"
+
+// IndexError reports a position outside the list.
+type IndexError struct {
+	Index int
+	Size  int
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("index %d out of range for list of size %d", e.Index, e.Size)
+}
+
+// seek finds the node at position i and the node in front of it, walking
+// from whichever end is closer. The caller must hold list.mu and ensure
+// 0 <= i < list.size.
+func (list *XLL[T]) seek(i int) (prev, curr uintptr) {
+	if i < list.size/2 {
+		curr = list.head
+		for ; i > 0; i-- {
+			prev, curr = curr, XOR(prev, list.node(curr).both)
+		}
+		return prev, curr
+	}
+	var next uintptr
+	curr = list.tail
+	for j := list.size - 1; j > i; j-- {
+		next, curr = curr, XOR(next, list.node(curr).both)
+	}
+	return XOR(next, list.node(curr).both), curr
+}
+
+// At returns the element at position i, counting from zero at the front.
+func (list *XLL[T]) At(i int) (T, error) {
+	list.rlock()
+	defer list.runlock()
+	return list.at(i, false)
+}
+
+// at is At counting from the front or the back. The caller must hold
+// list.mu.
+func (list *XLL[T]) at(i int, fromBack bool) (T, error) {
+	var zero T
+	if list.IsFreed() {
+		return zero, ErrFreedList
+	}
+	if i < 0 || i >= list.size {
+		return zero, &IndexError{Index: i, Size: list.size}
+	}
+	if fromBack {
+		i = list.size - 1 - i
+	}
+	_, curr := list.seek(i)
+	return list.node(curr).data, nil
+}
+
+// Set replaces the element at position i.
+func (list *XLL[T]) Set(i int, data T) error {
+	list.lock()
+	defer list.unlock()
+	if list.IsFreed() {
+		return ErrFreedList
+	}
+	if i < 0 || i >= list.size {
+		return &IndexError{Index: i, Size: list.size}
+	}
+	list.modified()
+	_, curr := list.seek(i)
+	list.node(curr).data = data
+	return nil
+}
+
+// InsertAt inserts data so that it ends up at position i. An index equal
+// to the size appends to the back.
+func (list *XLL[T]) InsertAt(i int, data T) error {
+	list.lock()
+	defer list.unlock()
+	if list.IsFreed() {
+		return ErrFreedList
+	}
+	if i < 0 || i > list.size {
+		return &IndexError{Index: i, Size: list.size}
+	}
+	if err := list.room(1); err != nil {
+		return err
+	}
+	if err := list.grow(); err != nil {
+		return err
+	}
+	list.modified()
+	left, right := list.tail, uintptr(0)
+	if i < list.size {
+		left, right = list.seek(i)
+	}
+	link, err := list.allocNode(data)
+	if err != nil {
+		return err
+	}
+	list.splice(link, left, right)
+	return nil
+}
+
+// RemoveAt removes the element at position i and returns it.
+func (list *XLL[T]) RemoveAt(i int) (T, error) {
+	list.lock()
+	defer list.unlock()
+	var zero T
+	if list.IsFreed() {
+		return zero, ErrFreedList
+	}
+	if i < 0 || i >= list.size {
+		return zero, &IndexError{Index: i, Size: list.size}
+	}
+	list.modified()
+	prev, curr := list.seek(i)
+	node := list.node(curr)
+	data := node.data
+	list.unsplice(curr, prev, XOR(prev, node.both))
+	return data, nil
+}
@@ -0,0 +1,76 @@
+package XLL
+
+import "iter"
+
+// Reverse reverses the list in constant time. Every node's both field
+// reads the same in either direction, so swapping the ends is enough.
+// Cursors on the list become stale.
+func (list *XLL[T]) Reverse() error {
+	list.lock()
+	defer list.unlock()
+	if list.IsFreed() {
+		return ErrFreedList
+	}
+	list.modified()
+	list.head, list.tail = list.tail, list.head
+	return nil
+}
+
+// ReverseView is a read-only view of a list in back-to-front order. It
+// reads the list live, so it reflects later changes to it.
+type ReverseView[T any] struct {
+	list *XLL[T]
+}
+
+// Reversed returns a back-to-front view of the list without changing it.
+func (list *XLL[T]) Reversed() *ReverseView[T] {
+	return &ReverseView[T]{list: list}
+}
+
+// Size returns the number of elements in the underlying list.
+func (v *ReverseView[T]) Size() int {
+	return v.list.Size()
+}
+
+// At returns the element at position i of the view, which is position
+// Size()-1-i of the list.
+func (v *ReverseView[T]) At(i int) (T, error) {
+	v.list.rlock()
+	defer v.list.runlock()
+	return v.list.at(i, true)
+}
+
+// PeekFront returns the front element of the view, the back of the list.
+func (v *ReverseView[T]) PeekFront() (T, error) {
+	return v.list.PeekBack()
+}
+
+// PeekBack returns the back element of the view, the front of the list.
+func (v *ReverseView[T]) PeekBack() (T, error) {
+	return v.list.PeekFront()
+}
+
+func (v *ReverseView[T]) TraverseForward(f func(T)) error {
+	return v.list.TraverseBackward(f)
+}
+
+func (v *ReverseView[T]) TraverseBackward(f func(T)) error {
+	return v.list.TraverseForward(f)
+}
+
+// All ranges over the view from its front, the back of the list.
+func (v *ReverseView[T]) All() iter.Seq[T] {
+	return v.list.Backward()
+}
+
+// Backward ranges over the view from its back, the front of the list.
+func (v *ReverseView[T]) Backward() iter.Seq[T] {
+	return v.list.All()
+}
+
+// Enumerate is like All but also yields positions within the view.
+func (v *ReverseView[T]) Enumerate() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		v.list.walk(false, yield)
+	}
+}
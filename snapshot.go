@@ -0,0 +1,166 @@
+package XLL
+
+import "iter"
+
+// viewChunk is how many elements a view copies out of a shared list per
+// lock acquisition.
+const viewChunk = 64
+
+// View is an immutable snapshot of a list. It can be read at leisure
+// while the list keeps changing: reading it never holds the list's lock
+// for longer than it takes to copy a few elements.
+//
+// Taking a snapshot copies nothing. Views taken at the same version of
+// the list share an epoch that reads the list's own nodes until the next
+// modification, which first copies the elements out once for all of
+// them. A view should be released when no longer needed, so writers stop
+// paying for that copy.
+type View[T any] struct {
+	list       *XLL[T]
+	epoch      *epoch[T]
+	head, tail uintptr
+	size       int
+}
+
+// epoch is the state shared by the views of one version of a list.
+type epoch[T any] struct {
+	items  []T  // the elements, once copied
+	copied bool // set under list.mu, never cleared
+	refs   int  // unreleased views still sharing the list's nodes
+}
+
+// Snapshot returns a view of the list as it is now. A snapshot of a freed
+// list is empty.
+func (list *XLL[T]) Snapshot() *View[T] {
+	list.lock()
+	defer list.unlock()
+	if list.IsFreed() {
+		return &View[T]{list: list, epoch: &epoch[T]{copied: true}}
+	}
+	if list.epoch == nil {
+		list.epoch = &epoch[T]{}
+	}
+	list.epoch.refs++
+	return &View[T]{list: list, epoch: list.epoch, head: list.head, tail: list.tail, size: list.size}
+}
+
+// detach copies the elements out for the views sharing the list's nodes,
+// ahead of a modification. The caller must hold list.mu.
+func (list *XLL[T]) detach() {
+	if e := list.epoch; e != nil {
+		e.items = list.elements()
+		e.copied = true
+		list.epoch = nil
+	}
+}
+
+// Release lets the view go. It then reads as empty, and once every view of
+// an epoch is released writers no longer copy elements for it.
+func (v *View[T]) Release() {
+	v.list.lock()
+	defer v.list.unlock()
+	e := v.epoch
+	if e == nil {
+		return
+	}
+	v.epoch = nil
+	if e.copied {
+		return
+	}
+	if e.refs--; e.refs == 0 && v.list.epoch == e {
+		v.list.epoch = nil
+	}
+}
+
+// Size returns the number of elements in the view.
+func (v *View[T]) Size() int {
+	if v.epoch == nil {
+		return 0
+	}
+	return v.size
+}
+
+// All returns an iterator over the view from front to back.
+func (v *View[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		v.walk(true, yield)
+	}
+}
+
+// Backward returns an iterator over the view from back to front.
+func (v *View[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		v.walk(false, yield)
+	}
+}
+
+// TraverseForward calls f on every element from front to back.
+func (v *View[T]) TraverseForward(f func(T)) {
+	for data := range v.All() {
+		f(data)
+	}
+}
+
+// TraverseBackward calls f on every element from back to front.
+func (v *View[T]) TraverseBackward(f func(T)) {
+	for data := range v.Backward() {
+		f(data)
+	}
+}
+
+// ToSlice returns the elements from front to back.
+func (v *View[T]) ToSlice() []T {
+	s := make([]T, 0, v.Size())
+	for data := range v.All() {
+		s = append(s, data)
+	}
+	return s
+}
+
+// walk yields the elements from one end. While the epoch still shares the
+// list's nodes it copies them out a chunk at a time, so that yield never
+// runs under the list's lock. Until the epoch is copied the list is
+// unchanged, so the position reached stays valid between chunks.
+func (v *View[T]) walk(forward bool, yield func(T) bool) {
+	e := v.epoch
+	if e == nil {
+		return
+	}
+	var prev, curr uintptr
+	chunk := make([]T, 0, min(v.size, viewChunk))
+	for i := 0; i < v.size; {
+		v.list.rlock()
+		if e.copied {
+			v.list.runlock()
+			for ; i < v.size; i++ {
+				j := i
+				if !forward {
+					j = v.size - 1 - i
+				}
+				if !yield(e.items[j]) {
+					return
+				}
+			}
+			return
+		}
+		if i == 0 {
+			curr = v.tail
+			if forward {
+				curr = v.head
+			}
+		}
+		chunk = chunk[:0]
+		for len(chunk) < cap(chunk) && curr != 0 {
+			node := v.list.node(curr)
+			chunk = append(chunk, node.data)
+			prev, curr = curr, XOR(prev, node.both)
+		}
+		v.list.runlock()
+		for _, data := range chunk {
+			i++
+			if !yield(data) {
+				return
+			}
+		}
+	}
+}
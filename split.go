@@ -0,0 +1,135 @@
+package XLL
+
+// SplitAt cuts the list in front of position i and returns the elements
+// from i onwards as a new list configured like this one. Splitting at the
+// size returns an empty list.
+//
+// Nodes of both halves share blocks, so the shorter half is copied into a
+// block of its own; if that is the front half, the new list takes over
+// the existing blocks instead. Either way each list ends up owning and
+// pinning only blocks the other no longer uses, and freeing one leaves
+// the other intact.
+func (list *XLL[T]) SplitAt(i int) (*XLL[T], error) {
+	list.lock()
+	defer list.unlock()
+	if list.IsFreed() {
+		return nil, ErrFreedList
+	}
+	if i < 0 || i > list.size {
+		return nil, &IndexError{Index: i, Size: list.size}
+	}
+	rest := list.sibling()
+	if i == list.size {
+		return rest, nil
+	}
+	prev, curr := list.seek(i)
+	if err := list.split(rest, prev, curr, i); err != nil {
+		return nil, err
+	}
+	return rest, nil
+}
+
+// Split cuts the list in front of the element under the cursor and
+// returns that element and everything behind it as a new list, as
+// SplitAt does. The cursor becomes stale.
+func (c *Cursor[T]) Split() (*XLL[T], error) {
+	list := c.list
+	list.lock()
+	defer list.unlock()
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	rest := list.sibling()
+	if c.curr == 0 {
+		return rest, nil
+	}
+
+	// Find the cursor's position by walking outwards from it in both
+	// directions until one of the ends is reached.
+	var i int
+	fprev, fcurr := c.prev, c.curr
+	bcurr, bnext := c.prev, c.curr
+	for k := 0; ; k++ {
+		if bcurr == 0 {
+			i = k
+			break
+		}
+		if fcurr == 0 {
+			i = list.size - k
+			break
+		}
+		fprev, fcurr = fcurr, XOR(fprev, list.node(fcurr).both)
+		bcurr, bnext = XOR(list.node(bcurr).both, bnext), bcurr
+	}
+	if err := list.split(rest, c.prev, c.curr, i); err != nil {
+		return nil, err
+	}
+	return rest, nil
+}
+
+// sibling returns an empty list configured like this one.
+func (list *XLL[T]) sibling() *XLL[T] {
+	return New[T](func(other *XLL[T]) {
+		other.blockSize = list.blockSize
+		other.growthRate = list.growthRate
+		other.indexed = list.indexed
+		other.unsync = list.unsync
+		other.maxSize = list.maxSize
+		other.policy = list.policy
+		other.codec = list.codec
+	})
+}
+
+// split moves the nodes from curr, which sits at position i behind prev,
+// to the empty list rest. The caller must hold list.mu; rest must not be
+// reachable by anyone else yet.
+func (list *XLL[T]) split(rest *XLL[T], prev, curr uintptr, i int) error {
+	list.modified()
+	if prev != 0 {
+		list.flipLink(prev, curr)
+	}
+	count := list.size - i
+
+	// A file-backed list never gives its mapped arena away.
+	if count <= i || list.file != nil {
+		list.tail = prev
+		if prev == 0 {
+			list.head = 0
+		}
+		rest.addBlock(count)
+		return list.moveRun(rest, curr, count)
+	}
+
+	// Hand the blocks over to rest and copy the front half back out.
+	head := list.head
+	rest.blocks, list.blocks = list.blocks, nil
+	rest.capacity, list.capacity = list.capacity, 0
+	rest.size, list.size = list.size, 0
+	rest.head, rest.tail = curr, list.tail
+	list.head, list.tail = 0, 0
+	if i > 0 {
+		list.addBlock(i)
+		return rest.moveRun(list, head, i)
+	}
+	return nil
+}
+
+// moveRun appends copies of the count nodes starting at from, one end of
+// a chain already cut loose from the rest of the list, to the back of dst
+// and releases them. The caller must hold list.mu.
+func (list *XLL[T]) moveRun(dst *XLL[T], from uintptr, count int) error {
+	var prev uintptr
+	curr := from
+	for ; count > 0; count-- {
+		node := list.node(curr)
+		next := XOR(prev, node.both)
+		link, err := dst.allocNode(node.data)
+		if err != nil {
+			return err
+		}
+		dst.splice(link, dst.tail, 0)
+		list.release(curr)
+		prev, curr = curr, next
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package XLL
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// SPSC is an XOR linked queue for exactly one producer goroutine and one
+// consumer goroutine. It uses no locks: the two sides only meet on the
+// element count and on the both field of the node between them, which
+// are updated atomically.
+//
+// InsertBack may only be called by the producer, PopFront and PeekFront
+// only by the consumer. Size may be called from either side.
+type SPSC[T any] struct {
+	// Consumer side. head is a spent node kept in front of the front
+	// element, so the producer never has to update head.
+	head      uintptr
+	headBlock *Block[T]
+	headSlot  int
+
+	// Producer side.
+	tail      uintptr
+	tailBlock *Block[T]
+	tailSlot  int
+
+	size      atomic.Int64
+	blockSize int
+}
+
+// NewSPSC returns an empty single-producer single-consumer queue. Blocks
+// are filled in order and dropped once the consumer has passed them, so
+// each has the size set by WithBlockSize; other options do not apply.
+func NewSPSC[T any](options ...Option[T]) *SPSC[T] {
+	q := &SPSC[T]{blockSize: settings(options).blockSize}
+	q.tailBlock = q.newBlock()
+	q.headBlock = q.tailBlock
+	q.tail = uintptr(unsafe.Pointer(&q.tailBlock.nodes[0]))
+	q.head = q.tail
+	return q
+}
+
+func (q *SPSC[T]) newBlock() *Block[T] {
+	// The slice is full length up front: the consumer indexes the block
+	// while the producer is still filling it. Blocks are not pinned, as
+	// no single goroutine owns them for their whole life.
+	return &Block[T]{nodes: make([]Node[T], q.blockSize)}
+}
+
+// xorBoth XORs v into a node's both field, which the other side may be
+// updating at the same time.
+func xorBoth(both *uintptr, v uintptr) {
+	for {
+		old := atomic.LoadUintptr(both)
+		if atomic.CompareAndSwapUintptr(both, old, old^v) {
+			return
+		}
+	}
+}
+
+// InsertBack adds data at the back of the queue. Producer only.
+func (q *SPSC[T]) InsertBack(data T) {
+	if q.tailSlot++; q.tailSlot == len(q.tailBlock.nodes) {
+		block := q.newBlock()
+		q.tailBlock.next = block
+		q.tailBlock = block
+		q.tailSlot = 0
+	}
+	node := &q.tailBlock.nodes[q.tailSlot]
+	link := uintptr(unsafe.Pointer(node))
+	node.data = data
+	node.both = q.tail
+	xorBoth(&nodeAt[T](q.tail).both, link)
+	q.tail = link
+	// Publishing the count hands the node over to the consumer.
+	q.size.Add(1)
+}
+
+// PopFront removes and returns the front element. Consumer only.
+func (q *SPSC[T]) PopFront() (T, error) {
+	var zero T
+	if q.size.Load() == 0 {
+		return zero, ErrEmptyList
+	}
+	// The spent head has no front neighbour, so its both field is just
+	// the link of the front element.
+	front := atomic.LoadUintptr(&nodeAt[T](q.head).both)
+	node := nodeAt[T](front)
+	data := node.data
+	node.data = zero
+	xorBoth(&node.both, q.head)
+	q.head = front
+
+	// The front element is now the spent head. Once it has moved into
+	// the next block, nothing refers to the previous one any more.
+	if q.headSlot++; q.headSlot == len(q.headBlock.nodes) {
+		block := q.headBlock
+		q.headBlock = block.next
+		q.headSlot = 0
+		block.next = nil
+	}
+	q.size.Add(-1)
+	return data, nil
+}
+
+// PeekFront returns the front element without removing it. Consumer only.
+func (q *SPSC[T]) PeekFront() (T, error) {
+	var zero T
+	if q.size.Load() == 0 {
+		return zero, ErrEmptyList
+	}
+	front := atomic.LoadUintptr(&nodeAt[T](q.head).both)
+	return nodeAt[T](front).data, nil
+}
+
+// Size returns the number of queued elements. Called from either side it
+// may be out of date as soon as it returns.
+func (q *SPSC[T]) Size() int {
+	return int(q.size.Load())
+}
@@ -0,0 +1,68 @@
+package XLL
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestSPSC(t *testing.T) {
+	q := NewSPSC[int](WithBlockSize[int](4))
+
+	// Test empty queue
+	if _, err := q.PopFront(); !errors.Is(err, ErrEmptyList) {
+		t.Errorf("Expected ErrEmptyList, got %v", err)
+	}
+	if _, err := q.PeekFront(); !errors.Is(err, ErrEmptyList) {
+		t.Errorf("Expected ErrEmptyList, got %v", err)
+	}
+
+	// Test FIFO order across several blocks
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 10; i++ {
+			q.InsertBack(i)
+		}
+		if q.Size() != 10 {
+			t.Errorf("Expected size 10, got %d", q.Size())
+		}
+		if v, err := q.PeekFront(); err != nil || v != 0 {
+			t.Errorf("Expected PeekFront to return 0, got %d (%v)", v, err)
+		}
+		for i := 0; i < 10; i++ {
+			if v, err := q.PopFront(); err != nil || v != i {
+				t.Errorf("Expected PopFront to return %d, got %d (%v)", i, v, err)
+			}
+		}
+		if q.Size() != 0 {
+			t.Errorf("Expected size 0, got %d", q.Size())
+		}
+	}
+}
+
+func TestSPSCConcurrent(t *testing.T) {
+	q := NewSPSC[int](WithBlockSize[int](16))
+	n := 100000
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			q.InsertBack(i)
+		}
+	}()
+
+	for i := 0; i < n; {
+		v, err := q.PopFront()
+		if errors.Is(err, ErrEmptyList) {
+			continue
+		}
+		if err != nil || v != i {
+			t.Fatalf("Expected PopFront to return %d, got %d (%v)", i, v, err)
+		}
+		i++
+	}
+	wg.Wait()
+	if q.Size() != 0 {
+		t.Errorf("Expected size 0, got %d", q.Size())
+	}
+}
@@ -0,0 +1,100 @@
+package XLL
+
+import "context"
+
+// PopFrontWait removes and returns the front element, waiting for one to
+// be inserted if the list is empty. It gives up with the context's error
+// once ctx is done, or with ErrFreedList if the list is freed meanwhile.
+//
+// A list built WithoutLocking cannot be changed by anyone while its only
+// goroutine waits, so there it returns ErrEmptyList instead of waiting.
+func (list *XLL[T]) PopFrontWait(ctx context.Context) (T, error) {
+	return list.popWait(ctx, true)
+}
+
+// PopBackWait is like PopFrontWait but removes the back element.
+func (list *XLL[T]) PopBackWait(ctx context.Context) (T, error) {
+	return list.popWait(ctx, false)
+}
+
+// PushBackWait inserts data at the back, waiting for room if the list is
+// bounded by WithMaxSize with OverflowReject and full; other policies
+// never wait. It gives up like PopFrontWait, and returns ErrFullList
+// rather than wait on a list built WithoutLocking.
+func (list *XLL[T]) PushBackWait(ctx context.Context, data T) error {
+	return list.pushWait(ctx, data, false)
+}
+
+// PushFrontWait is like PushBackWait but inserts data at the front.
+func (list *XLL[T]) PushFrontWait(ctx context.Context, data T) error {
+	return list.pushWait(ctx, data, true)
+}
+
+func (list *XLL[T]) popWait(ctx context.Context, front bool) (T, error) {
+	var zero T
+	list.lock()
+	defer list.unlock()
+	err := list.wait(ctx, func() error {
+		if list.head == 0 {
+			return ErrEmptyList
+		}
+		return nil
+	})
+	if err != nil {
+		return zero, err
+	}
+	if err := list.logDelete(front); err != nil {
+		return zero, err
+	}
+	return list.pop(front), nil
+}
+
+func (list *XLL[T]) pushWait(ctx context.Context, data T, front bool) error {
+	list.lock()
+	defer list.unlock()
+	err := list.wait(ctx, func() error {
+		if list.policy != OverflowReject {
+			return nil
+		}
+		return list.room(1)
+	})
+	if err != nil {
+		return err
+	}
+	return list.add(data, front)
+}
+
+// wait blocks until ready returns nil, ctx is done or the list is freed.
+// The caller must hold list.mu, which is released while waiting.
+func (list *XLL[T]) wait(ctx context.Context, ready func() error) error {
+	var stop func() bool
+	for {
+		if list.IsFreed() {
+			return ErrFreedList
+		}
+		err := ready()
+		if err == nil || list.unsync {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if stop == nil {
+			if list.cond.L == nil {
+				list.cond.L = &list.mu
+			}
+			// Broadcasting under the lock means the wakeup cannot slip in
+			// between the check of ctx above and the wait below.
+			stop = context.AfterFunc(ctx, func() {
+				list.mu.Lock()
+				list.cond.Broadcast()
+				list.mu.Unlock()
+			})
+			defer stop()
+		}
+		list.waiters++
+		list.cond.Wait()
+		list.waiters--
+	}
+}
@@ -0,0 +1,362 @@
+package XLL
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+)
+
+// A write-ahead log is a sequence of records, each an op byte, a 32-bit
+// payload length, the payload and a CRC-32 (IEEE) of all three, all
+// little-endian. Insertions carry the element as written by the codec,
+// deletions nothing, and snapshots the whole list in the form WriteTo
+// uses.
+const (
+	walInsertFront byte = iota + 1
+	walInsertBack
+	walDeleteFront
+	walDeleteBack
+	walSnapshot
+)
+
+// wal is the write-ahead log of a list built WithWAL.
+type wal[T any] struct {
+	w       io.Writer
+	codec   Codec[T] // nil for the list's own codec
+	every   int      // records between compactions, 0 for never
+	next    func() (io.Writer, error)
+	records int   // records written since the last snapshot
+	logged  bool  // the change under way has been recorded
+	pending bool  // the list has changed since its last record
+	err     error // set while w holds a damaged log
+	buf     bytes.Buffer
+}
+
+// WALError reports a write-ahead log that Recover could not replay to its
+// end: a record cut short by a crash, a damaged record or one that could
+// not be applied. Offset is the length of the log up to the end of the
+// last record replayed; whatever follows it is lost.
+type WALError struct {
+	Offset int64
+	Err    error
+}
+
+func (e *WALError) Error() string {
+	return fmt.Sprintf("write-ahead log unreadable after offset %d: %v", e.Offset, e.Err)
+}
+
+func (e *WALError) Unwrap() error {
+	return e.Err
+}
+
+// WithWAL makes the list record its changes in a write-ahead log on w, so
+// that Recover can rebuild it after a crash. Elements are encoded with
+// codec, or as WriteTo encodes them if codec is nil.
+//
+// InsertFront, InsertBack, DeleteFront, DeleteBack, PopFront, PopBack and
+// their waiting forms write a short record before changing the list; if
+// the write fails they return its error and leave the list unchanged.
+// Every other change is recorded once it is made, as a snapshot of the
+// whole list, which costs time and space in proportion to its size. If
+// such a snapshot cannot be written it is tried again on the next change,
+// and a record that is still missing fails the next end operation.
+//
+// Each record is written with a single call to w, which the list does not
+// sync or close: a durable log needs a writer that reaches stable storage
+// before returning, such as a file opened with os.O_SYNC. Freeing the list
+// does not touch the log.
+func WithWAL[T any](w io.Writer, codec Codec[T]) Option[T] {
+	return func(list *XLL[T]) {
+		list.wal = &wal[T]{w: w, codec: codec}
+	}
+}
+
+// WithCompaction makes a list built WithWAL compact its log once every
+// records have been written since the last snapshot: it calls next for a
+// new writer, writes a snapshot of the list to it and logs there from then
+// on. The old writer is no longer used and may be discarded once the
+// snapshot is safely stored. If next or the snapshot fails, the end
+// operation that triggered the compaction returns the error without
+// changing the list, and the old writer stays in use. It must follow
+// WithWAL in the options.
+func WithCompaction[T any](every int, next func() (io.Writer, error)) Option[T] {
+	return func(list *XLL[T]) {
+		if list.wal != nil && every > 0 && next != nil {
+			list.wal.every = every
+			list.wal.next = next
+		}
+	}
+}
+
+// Compact writes a snapshot of the list to w and makes w its write-ahead
+// log, replacing the old one. A list without a log starts one on w, with
+// elements encoded as WriteTo encodes them.
+func (list *XLL[T]) Compact(w io.Writer) error {
+	list.lock()
+	defer list.unlock()
+	if list.IsFreed() {
+		return ErrFreedList
+	}
+	if list.wal != nil {
+		return list.rotate(w)
+	}
+	list.wal = &wal[T]{}
+	if err := list.rotate(w); err != nil {
+		list.wal = nil
+		return err
+	}
+	return nil
+}
+
+// Recover builds a list from the write-ahead log read from r, replaying
+// its records with the same operations that wrote them, so options such
+// as WithMaxSize should match those of the list that wrote the log.
+//
+// If the log does not end after a whole record, as when a crash cut the
+// last one short, Recover returns the list as replayed so far with a
+// *WALError giving the offset where the good records end. The error wraps
+// io.ErrUnexpectedEOF for a record cut short and ErrCorrupt for one
+// failing its checksum.
+//
+// If options include WithWAL, the recovered list starts its new log with
+// a snapshot of whatever it recovered. After a *WALError it leaves the
+// damaged log alone instead, and end operations fail with that error
+// until Compact moves the list to a sound log: a fresh writer, or the old
+// log truncated to the offset.
+func Recover[T any](r io.Reader, options ...Option[T]) (*XLL[T], error) {
+	list := New(options...)
+	log := list.wal
+	list.wal = nil
+	err := list.replay(r, log)
+	list.wal = log
+	if log == nil {
+		return list, err
+	}
+	var walErr *WALError
+	if errors.As(err, &walErr) {
+		log.err = err
+		return list, err
+	}
+	list.lock()
+	defer list.unlock()
+	if e := list.rotate(log.w); err == nil {
+		err = e
+	}
+	return list, err
+}
+
+// replay applies the records read from r to the list.
+func (list *XLL[T]) replay(r io.Reader, log *wal[T]) error {
+	codec, err := list.walCodec(log)
+	if err != nil {
+		return err
+	}
+	var payload bytes.Buffer
+	var offset int64
+	for n := 0; ; n++ {
+		op, size, err := readRecord(r, &payload)
+		if err == io.EOF {
+			return nil
+		}
+		if err == nil {
+			err = list.apply(op, &payload, codec)
+		}
+		if err != nil {
+			return &WALError{Offset: offset, Err: fmt.Errorf("record %d: %w", n, err)}
+		}
+		offset += size
+	}
+}
+
+// readRecord reads one record into payload and returns its op and size.
+// It returns io.EOF at the end of r and io.ErrUnexpectedEOF for a record
+// cut short.
+func readRecord(r io.Reader, payload *bytes.Buffer) (byte, int64, error) {
+	var head [5]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return 0, 0, err
+	}
+	crc := crc32.NewIEEE()
+	crc.Write(head[:])
+	payload.Reset()
+	// Copy rather than allocate the length up front, as it may be
+	// corrupt.
+	n := int64(binary.LittleEndian.Uint32(head[1:]))
+	if _, err := io.CopyN(payload, r, n); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, 0, err
+	}
+	crc.Write(payload.Bytes())
+	var sum uint32
+	if err := binary.Read(r, binary.LittleEndian, &sum); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, 0, err
+	}
+	if sum != crc.Sum32() {
+		return 0, 0, fmt.Errorf("%w: checksum mismatch", ErrCorrupt)
+	}
+	return head[0], 5 + n + 4, nil
+}
+
+// apply replays one record.
+func (list *XLL[T]) apply(op byte, payload *bytes.Buffer, codec Codec[T]) error {
+	switch op {
+	case walInsertFront, walInsertBack:
+		v, err := codec.Decode(payload)
+		if err != nil {
+			return corrupt("decoding element", err)
+		}
+		return list.insert(v, op == walInsertFront)
+	case walDeleteFront, walDeleteBack:
+		return list.delete(op == walDeleteFront)
+	case walSnapshot:
+		values, header, _, err := decode(payload, codec)
+		if err != nil {
+			return err
+		}
+		list.lock()
+		defer list.unlock()
+		return list.replace(values, int(header.BlockSize), header.GrowthRate)
+	}
+	return fmt.Errorf("%w: unknown op %d", ErrCorrupt, op)
+}
+
+// walCodec returns the codec for the elements in log.
+func (list *XLL[T]) walCodec(log *wal[T]) (Codec[T], error) {
+	if log != nil && log.codec != nil {
+		return log.codec, nil
+	}
+	return list.elementCodec()
+}
+
+// logInsert records the insertion of data at one end, unless the list is
+// full and its policy leaves it as it is. The caller must hold list.mu.
+func (list *XLL[T]) logInsert(data T, front bool) error {
+	if list.wal == nil || (list.room(1) != nil && list.policy != OverflowDropOldest) {
+		return nil
+	}
+	op := walInsertBack
+	if front {
+		op = walInsertFront
+	}
+	return list.record(op, func(w io.Writer, codec Codec[T]) error {
+		return codec.Encode(w, data)
+	})
+}
+
+// logDelete records the removal of an element at one end. The caller
+// must hold list.mu.
+func (list *XLL[T]) logDelete(front bool) error {
+	if list.wal == nil {
+		return nil
+	}
+	op := walDeleteBack
+	if front {
+		op = walDeleteFront
+	}
+	return list.record(op, nil)
+}
+
+// record writes a record of the change about to be made, first catching
+// up on a missing snapshot and compacting if due. The caller must hold
+// list.mu.
+func (list *XLL[T]) record(op byte, payload func(io.Writer, Codec[T]) error) error {
+	log := list.wal
+	if log.err != nil {
+		return log.err
+	}
+	switch {
+	case log.every > 0 && log.records >= log.every:
+		w, err := log.next()
+		if err != nil {
+			return fmt.Errorf("compacting write-ahead log: %w", err)
+		}
+		if err := list.rotate(w); err != nil {
+			return fmt.Errorf("compacting write-ahead log: %w", err)
+		}
+	case log.pending:
+		if err := list.snapshotWAL(); err != nil {
+			return err
+		}
+	}
+	if err := list.writeRecord(log.w, op, payload); err != nil {
+		return err
+	}
+	log.records++
+	log.logged = true
+	return nil
+}
+
+// rotate writes a snapshot of the list to w and logs there from then on.
+// The caller must hold list.mu.
+func (list *XLL[T]) rotate(w io.Writer) error {
+	if err := list.writeRecord(w, walSnapshot, list.encodeSnapshot); err != nil {
+		return err
+	}
+	list.wal.w = w
+	list.wal.records = 0
+	list.wal.pending = false
+	list.wal.err = nil
+	return nil
+}
+
+// snapshotWAL writes a snapshot of the list to its log. The caller must
+// hold list.mu.
+func (list *XLL[T]) snapshotWAL() error {
+	if err := list.writeRecord(list.wal.w, walSnapshot, list.encodeSnapshot); err != nil {
+		return err
+	}
+	list.wal.records = 0
+	list.wal.pending = false
+	return nil
+}
+
+func (list *XLL[T]) encodeSnapshot(w io.Writer, codec Codec[T]) error {
+	_, err := list.encode(w, codec)
+	return err
+}
+
+// writeRecord builds a record and writes it to w in one call. The caller
+// must hold list.mu.
+func (list *XLL[T]) writeRecord(w io.Writer, op byte, payload func(io.Writer, Codec[T]) error) error {
+	codec, err := list.walCodec(list.wal)
+	if err != nil {
+		return err
+	}
+	buf := &list.wal.buf
+	buf.Reset()
+	buf.Write([]byte{op, 0, 0, 0, 0})
+	if payload != nil {
+		if err := payload(buf, codec); err != nil {
+			return err
+		}
+	}
+	record := buf.Bytes()
+	if uint64(len(record)-5) > math.MaxUint32 {
+		return fmt.Errorf("XLL: write-ahead log record of %d bytes is too large", len(record)-5)
+	}
+	binary.LittleEndian.PutUint32(record[1:], uint32(len(record)-5))
+	buf.Write(binary.LittleEndian.AppendUint32(nil, crc32.ChecksumIEEE(record)))
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// flushWAL ends a critical section of a list with a log, writing a
+// snapshot if the list changed without a record. The caller must hold
+// list.mu.
+func (list *XLL[T]) flushWAL() {
+	log := list.wal
+	if log.pending && log.err == nil && !list.IsFreed() {
+		// On failure the snapshot stays pending for the next change.
+		list.snapshotWAL()
+	}
+	log.logged = false
+}
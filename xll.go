@@ -5,14 +5,19 @@ import (
 	"runtime"
 	"sync"
 	"sync/atomic"
-	"unsafe"
 )
 
 // Common errors
 var (
-	ErrFreedList    = errors.New("operation on freed list")
-	ErrEmptyList    = errors.New("operation on empty list")
-	ErrAlreadyFreed = errors.New("list already freed")
+	ErrFreedList       = errors.New("operation on freed list")
+	ErrEmptyList       = errors.New("operation on empty list")
+	ErrAlreadyFreed    = errors.New("list already freed")
+	ErrStaleCursor     = errors.New("cursor invalidated by list modification")
+	ErrSameList        = errors.New("operation needs two distinct lists")
+	ErrFullList        = errors.New("operation on full list")
+	ErrNoCodec         = errors.New("no codec for element type")
+	ErrCorrupt         = errors.New("corrupt encoded list")
+	ErrPointerElements = errors.New("element type contains pointers")
 )
 
 type Node[T any] struct {
@@ -21,24 +26,45 @@ type Node[T any] struct {
 }
 
 type Block[T any] struct {
-	nodes []Node[T]
-	next  *Block[T]
+	nodes  []Node[T]
+	next   *Block[T]
+	live   int // slots holding linked nodes
+	free   int // index+1 of the first dead slot, 0 if none
+	pinner runtime.Pinner
 }
 
 type XLL[T any] struct {
-	head       *Node[T]
-	tail       *Node[T]
+	head       uintptr
+	tail       uintptr
 	blocks     *Block[T]
 	size       int
-	pinner     *runtime.Pinner
+	capacity   int
 	blockSize  int
 	growthRate float64
+	indexed    bool
+	pinning    bool // lists not made by New have no finalizer to unpin blocks
+	unsync     bool
+	maxSize    int // 0 if unbounded
+	policy     OverflowPolicy
+	version    uint64
 	freed      atomic.Bool
 	mu         sync.RWMutex
+	guard      guard
+	cond       sync.Cond // signalled on every modification while waiters > 0
+	waiters    int
+	epoch      *epoch[T] // shared by views of the current version, if any
+	codec      Codec[T]
+	file       *mappedFile // set for lists from OpenFile
+	wal        *wal[T]     // set for lists built WithWAL
 }
 
 type Option[T any] func(*XLL[T])
 
+const (
+	defaultBlockSize  = 1024
+	defaultGrowthRate = 2.0
+)
+
 func WithBlockSize[T any](size int) Option[T] {
 	return func(list *XLL[T]) {
 		if size > 0 {
@@ -58,9 +84,22 @@ func WithGrowthRate[T any](rate float64) Option[T] {
 func WithInitialCapacity[T any](capacity int) Option[T] {
 	return func(list *XLL[T]) {
 		if capacity > 0 {
-			initialBlock := &Block[T]{nodes: make([]Node[T], 0, capacity)}
-			list.blocks = initialBlock
-			list.pinner.Pin(initialBlock)
+			list.addBlock(capacity)
+		}
+	}
+}
+
+// WithIndexLinks stores nodes in a single growable arena and links them
+// by XORing slot indices instead of addresses. Nothing is pinned and no
+// address is hidden from the garbage collector or the race detector, at
+// the cost of an indexing step on every hop and a copy when the arena
+// grows.
+func WithIndexLinks[T any]() Option[T] {
+	return func(list *XLL[T]) {
+		list.indexed = true
+		if list.blocks != nil {
+			// A block made by WithInitialCapacity becomes the arena.
+			list.blocks.pinner.Unpin()
 		}
 	}
 }
@@ -68,147 +107,239 @@ func WithInitialCapacity[T any](capacity int) Option[T] {
 // New function
 
 func New[T any](options ...Option[T]) *XLL[T] {
+	list := configure(options)
+	runtime.SetFinalizer(list, (*XLL[T]).Free)
+	return list
+}
+
+func configure[T any](options []Option[T]) *XLL[T] {
 	list := &XLL[T]{
-		blockSize:  1024,
-		growthRate: 2.0,
-		pinner:     new(runtime.Pinner),
+		blockSize:  defaultBlockSize,
+		growthRate: defaultGrowthRate,
+		pinning:    true,
 	}
 	for _, option := range options {
 		option(list)
 	}
-	runtime.SetFinalizer(list, (*XLL[T]).Free)
 	return list
 }
 
-func (list *XLL[T]) newNode(data T) *Node[T] {
-	list.mu.Lock()
-	defer list.mu.Unlock()
-
-	if list.blocks == nil || len(list.blocks.nodes) == cap(list.blocks.nodes) {
-		newCapacity := list.blockSize
-		if list.blocks != nil {
-			newCapacity = int(float64(cap(list.blocks.nodes)) * list.growthRate)
-		}
-		newBlock := &Block[T]{nodes: make([]Node[T], 0, newCapacity)}
-		list.pinner.Pin(newBlock)
-		newBlock.next = list.blocks
-		list.blocks = newBlock
-	}
-
-	node := Node[T]{data: data}
-	list.blocks.nodes = append(list.blocks.nodes, node)
-	list.size++
-	return &list.blocks.nodes[len(list.blocks.nodes)-1]
+// settings reads options for types that share XLL's configuration but
+// keep their own storage.
+func settings[T any](options []Option[T]) *XLL[T] {
+	list := configure(options)
+	list.clear()
+	return list
 }
 
 func (list *XLL[T]) Free() error {
-	list.mu.Lock()
-	defer list.mu.Unlock()
+	list.lock()
+	defer list.unlock()
 
 	if !list.freed.CompareAndSwap(false, true) {
 		return ErrAlreadyFreed
 	}
 
-	// Unpin all pinned objects
-	list.pinner.Unpin()
+	list.modified()
+	var err error
+	if list.file != nil {
+		err = list.closeFile()
+	}
+	list.clear()
 
-	// Reset all fields
-	list.head = nil
-	list.tail = nil
+	// Remove the finalizer
+	runtime.SetFinalizer(list, nil)
+
+	return err
+}
+
+// clear unpins and drops every block, leaving the list empty. A
+// file-backed list keeps its arena and rewinds it instead. The caller
+// must hold list.mu.
+func (list *XLL[T]) clear() {
+	if list.file != nil {
+		arena := list.blocks
+		arena.nodes = arena.nodes[:0]
+		arena.live = 0
+		arena.free = 0
+		list.head = 0
+		list.tail = 0
+		list.size = 0
+		return
+	}
+	for block := list.blocks; block != nil; block = block.next {
+		block.pinner.Unpin()
+	}
+	list.head = 0
+	list.tail = 0
 	list.blocks = nil
 	list.size = 0
-	list.pinner = nil
+	list.capacity = 0
+}
 
-	// Remove the finalizer
-	runtime.SetFinalizer(list, nil)
+// modified records a change to the list, invalidating its cursors and
+// waking goroutines waiting for one. It must be called before the change
+// is made, so that views still sharing the nodes can copy them first. The
+// caller must hold list.mu.
+func (list *XLL[T]) modified() {
+	list.version++
+	list.detach()
+	if list.wal != nil && !list.wal.logged {
+		list.wal.pending = true
+	}
+	if list.waiters > 0 {
+		list.cond.Broadcast()
+	}
+}
 
+// room returns ErrFullList unless n more elements fit within the size
+// bound. The caller must hold list.mu.
+func (list *XLL[T]) room(n int) error {
+	if list.maxSize > 0 && list.size+n > list.maxSize {
+		return ErrFullList
+	}
 	return nil
 }
 
 func (list *XLL[T]) delete(front bool) error {
+	_, err := list.remove(front)
+	return err
+}
+
+func (list *XLL[T]) remove(front bool) (T, error) {
+	var zero T
+	list.lock()
+	defer list.unlock()
 	if list.IsFreed() {
-		return ErrFreedList
+		return zero, ErrFreedList
 	}
-	list.mu.Lock()
-	defer list.mu.Unlock()
 
-	if list.head == nil {
-		return ErrEmptyList
+	if list.head == 0 {
+		return zero, ErrEmptyList
 	}
-
-	if list.head == list.tail {
-		list.head = nil
-		list.tail = nil
-		list.blocks = nil
-		list.size = 0
-		return nil
+	if err := list.logDelete(front); err != nil {
+		return zero, err
 	}
+	return list.pop(front), nil
+}
 
-	if front {
-		nextNode := (*Node[T])(unsafe.Pointer(list.head.both))
-		nextNode.both = XOR(uintptr(unsafe.Pointer(list.head)), nextNode.both)
-		list.head = nextNode
+// pop unlinks and returns an end element of a non-empty list. The caller
+// must hold list.mu.
+func (list *XLL[T]) pop(front bool) T {
+	list.modified()
+	removed := list.head
+	if list.head == list.tail {
+		list.head = 0
+		list.tail = 0
+	} else if front {
+		next := list.node(list.head).both
+		nextNode := list.node(next)
+		nextNode.both = XOR(list.head, nextNode.both)
+		list.head = next
 	} else {
-		prevNode := (*Node[T])(unsafe.Pointer(list.tail.both))
-		prevNode.both = XOR(prevNode.both, uintptr(unsafe.Pointer(list.tail)))
-		list.tail = prevNode
+		removed = list.tail
+		prev := list.node(list.tail).both
+		prevNode := list.node(prev)
+		prevNode.both = XOR(prevNode.both, list.tail)
+		list.tail = prev
 	}
 
-	if len(list.blocks.nodes) == 1 {
-		list.blocks = list.blocks.next
-	} else if front {
-		list.blocks.nodes = list.blocks.nodes[1:]
-	} else {
-		list.blocks.nodes = list.blocks.nodes[:len(list.blocks.nodes)-1]
+	data := list.node(removed).data
+	list.release(removed)
+	return data
+}
+
+func (list *XLL[T]) peek(front bool) (T, error) {
+	var zero T
+	list.rlock()
+	defer list.runlock()
+	if list.IsFreed() {
+		return zero, ErrFreedList
 	}
 
-	list.size--
-	return nil
+	if list.head == 0 {
+		return zero, ErrEmptyList
+	}
+	if front {
+		return list.node(list.head).data, nil
+	}
+	return list.node(list.tail).data, nil
 }
 
 func (list *XLL[T]) traverse(f func(T), forward bool) error {
+	list.rlock()
+	defer list.runlock()
 	if list.IsFreed() {
 		return ErrFreedList
 	}
-	list.mu.RLock()
-	defer list.mu.RUnlock()
 	var prev uintptr
-	var curr unsafe.Pointer
+	curr := list.tail
 	if forward {
-		curr = unsafe.Pointer(list.head)
-	} else {
-		curr = unsafe.Pointer(list.tail)
+		curr = list.head
 	}
-	for curr != nil {
-		node := (*Node[T])(curr)
+	for curr != 0 {
+		node := list.node(curr)
 		f(node.data)
-		next := XOR(prev, node.both)
-		prev = uintptr(curr)
-		// Conversion of a Pointer to an uintptr and back, with arithmetic.
-		// Explicitly following pattern (3)
-		curr = unsafe.Pointer(next)
+		prev, curr = curr, XOR(prev, node.both)
 	}
 	return nil
 }
 
 func (list *XLL[T]) insert(data T, front bool) error {
+	// Allocation and linking share one critical section, so a concurrent
+	// Free or delete never sees a node that is counted but not linked.
+	list.lock()
+	defer list.unlock()
 	if list.IsFreed() {
 		return ErrFreedList
 	}
-	newNode := list.newNode(data)
-	list.mu.Lock()
-	defer list.mu.Unlock()
+	return list.add(data, front)
+}
 
-	if list.head == nil {
+// add pushes data at one end, applying the overflow policy if the list
+// is full. The caller must hold list.mu.
+func (list *XLL[T]) add(data T, front bool) error {
+	// Grow first, so that a list that cannot grow is left unchanged and
+	// unlogged. A full list makes room by evicting instead, if at all.
+	if list.room(1) == nil {
+		if err := list.grow(); err != nil {
+			return err
+		}
+	}
+	if err := list.logInsert(data, front); err != nil {
+		return err
+	}
+	_, keep, err := list.fit(1, front)
+	if keep > 0 {
+		if err := list.push(data, front); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// push links data in at one end. The caller must hold list.mu.
+func (list *XLL[T]) push(data T, front bool) error {
+	if err := list.grow(); err != nil {
+		return err
+	}
+	list.modified()
+	newNode, err := list.allocNode(data)
+	if err != nil {
+		return err
+	}
+	if list.head == 0 {
 		list.head = newNode
 		list.tail = newNode
 	} else if front {
-		newNode.both = uintptr(unsafe.Pointer(list.head))
-		list.head.both = XOR(list.head.both, uintptr(unsafe.Pointer(newNode)))
+		list.node(newNode).both = list.head
+		head := list.node(list.head)
+		head.both = XOR(head.both, newNode)
 		list.head = newNode
 	} else {
-		newNode.both = uintptr(unsafe.Pointer(list.tail))
-		list.tail.both = XOR(list.tail.both, uintptr(unsafe.Pointer(newNode)))
+		list.node(newNode).both = list.tail
+		tail := list.node(list.tail)
+		tail.both = XOR(tail.both, newNode)
 		list.tail = newNode
 	}
 	return nil
@@ -222,6 +353,26 @@ func (list *XLL[T]) DeleteBack() error {
 	return list.delete(false)
 }
 
+// PopFront removes the front element and returns it.
+func (list *XLL[T]) PopFront() (T, error) {
+	return list.remove(true)
+}
+
+// PopBack removes the back element and returns it.
+func (list *XLL[T]) PopBack() (T, error) {
+	return list.remove(false)
+}
+
+// PeekFront returns the front element without removing it.
+func (list *XLL[T]) PeekFront() (T, error) {
+	return list.peek(true)
+}
+
+// PeekBack returns the back element without removing it.
+func (list *XLL[T]) PeekBack() (T, error) {
+	return list.peek(false)
+}
+
 func (list *XLL[T]) TraverseForward(f func(T)) error {
 	return list.traverse(f, true)
 }
@@ -1,10 +1,21 @@
 package XLL
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
 	"errors"
+	"io"
 	"math/rand/v2"
+	"os"
+	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -101,6 +112,232 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestPopPeek(t *testing.T) {
+	list := New[int]()
+	for _, v := range []int{1, 2, 3, 4} {
+		if err := list.InsertBack(v); err != nil {
+			t.Fatalf("InsertBack failed: %v", err)
+		}
+	}
+
+	// Test PeekFront and PeekBack
+	if v, err := list.PeekFront(); err != nil || v != 1 {
+		t.Errorf("Expected PeekFront to return 1, got %d (%v)", v, err)
+	}
+	if v, err := list.PeekBack(); err != nil || v != 4 {
+		t.Errorf("Expected PeekBack to return 4, got %d (%v)", v, err)
+	}
+	if list.Size() != 4 {
+		t.Errorf("Expected size 4 after peeking, got %d", list.Size())
+	}
+
+	// Test PopFront and PopBack
+	if v, err := list.PopFront(); err != nil || v != 1 {
+		t.Errorf("Expected PopFront to return 1, got %d (%v)", v, err)
+	}
+	if v, err := list.PopBack(); err != nil || v != 4 {
+		t.Errorf("Expected PopBack to return 4, got %d (%v)", v, err)
+	}
+	if list.Size() != 2 {
+		t.Errorf("Expected size 2 after popping, got %d", list.Size())
+	}
+	if v, err := list.PopBack(); err != nil || v != 3 {
+		t.Errorf("Expected PopBack to return 3, got %d (%v)", v, err)
+	}
+	if v, err := list.PopFront(); err != nil || v != 2 {
+		t.Errorf("Expected PopFront to return 2, got %d (%v)", v, err)
+	}
+
+	// Test empty list
+	if _, err := list.PopFront(); !errors.Is(err, ErrEmptyList) {
+		t.Errorf("Expected ErrEmptyList, got %v", err)
+	}
+	if _, err := list.PeekBack(); !errors.Is(err, ErrEmptyList) {
+		t.Errorf("Expected ErrEmptyList, got %v", err)
+	}
+
+	// Test freed list
+	if err := list.Free(); err != nil {
+		t.Errorf("Free failed: %v", err)
+	}
+	if _, err := list.PopBack(); !errors.Is(err, ErrFreedList) {
+		t.Errorf("Expected ErrFreedList, got %v", err)
+	}
+	if _, err := list.PeekFront(); !errors.Is(err, ErrFreedList) {
+		t.Errorf("Expected ErrFreedList, got %v", err)
+	}
+}
+
+func TestBlockAccounting(t *testing.T) {
+	exerciseBlocks(t, New[int](
+		WithBlockSize[int](8),
+		WithGrowthRate[int](1.5),
+	))
+}
+
+func TestIndexLinks(t *testing.T) {
+	list := New[int](
+		WithInitialCapacity[int](4),
+		WithIndexLinks[int](),
+		WithGrowthRate[int](1.5),
+	)
+	exerciseBlocks(t, list)
+
+	// Links are slot numbers rather than addresses
+	for _, v := range []int{1, 2, 3} {
+		if err := list.InsertBack(v); err != nil {
+			t.Fatalf("InsertBack failed: %v", err)
+		}
+	}
+	if list.head == 0 || list.head > uintptr(list.capacity) || list.tail > uintptr(list.capacity) {
+		t.Errorf("Expected slot links, got head %#x and tail %#x", list.head, list.tail)
+	}
+
+	if err := list.Free(); err != nil {
+		t.Errorf("Free failed: %v", err)
+	}
+	if err := list.InsertBack(1); !errors.Is(err, ErrFreedList) {
+		t.Errorf("Expected ErrFreedList, got %v", err)
+	}
+}
+
+func TestWithoutLocking(t *testing.T) {
+	list := New[int](WithoutLocking[int](), WithBlockSize[int](8))
+	exerciseBlocks(t, list)
+
+	// Test a split keeps the option
+	if err := list.InsertBackAll(1, 2, 3, 4); err != nil {
+		t.Fatalf("InsertBackAll failed: %v", err)
+	}
+	rest, err := list.SplitAt(list.Size() - 2)
+	if err != nil {
+		t.Fatalf("SplitAt failed: %v", err)
+	}
+	if !rest.unsync {
+		t.Errorf("Expected the split off list to skip locking")
+	}
+	expectElements(t, rest, 3, 4)
+
+	if err := list.Free(); err != nil {
+		t.Errorf("Free failed: %v", err)
+	}
+	if err := list.InsertBack(1); !errors.Is(err, ErrFreedList) {
+		t.Errorf("Expected ErrFreedList, got %v", err)
+	}
+}
+
+// exerciseBlocks interleaves inserts and deletes at both ends across many
+// blocks, checking the contents and block bookkeeping as it goes.
+func exerciseBlocks(t *testing.T, list *XLL[int]) {
+	t.Helper()
+	rng := rand.New(rand.NewPCG(1, 2))
+	var expected []int
+
+	for i := 0; i < 20000; i++ {
+		// Bias towards growth first and shrinking later so blocks are
+		// both added and released.
+		grow := rng.IntN(100) < 60
+		if i > 10000 {
+			grow = rng.IntN(100) < 40
+		}
+		switch {
+		case grow && rng.IntN(2) == 0:
+			if err := list.InsertFront(i); err != nil {
+				t.Fatalf("InsertFront failed: %v", err)
+			}
+			expected = append([]int{i}, expected...)
+		case grow:
+			if err := list.InsertBack(i); err != nil {
+				t.Fatalf("InsertBack failed: %v", err)
+			}
+			expected = append(expected, i)
+		case rng.IntN(2) == 0:
+			err := list.DeleteFront()
+			if len(expected) == 0 {
+				if !errors.Is(err, ErrEmptyList) {
+					t.Fatalf("Expected ErrEmptyList, got %v", err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("DeleteFront failed: %v", err)
+			}
+			expected = expected[1:]
+		default:
+			err := list.DeleteBack()
+			if len(expected) == 0 {
+				if !errors.Is(err, ErrEmptyList) {
+					t.Fatalf("Expected ErrEmptyList, got %v", err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("DeleteBack failed: %v", err)
+			}
+			expected = expected[:len(expected)-1]
+		}
+		if i%97 == 0 {
+			checkList(t, list, expected)
+		}
+	}
+	checkList(t, list, expected)
+
+	// Draining the list releases every block but the newest one
+	for list.Size() > 0 {
+		if err := list.DeleteFront(); err != nil {
+			t.Fatalf("DeleteFront failed: %v", err)
+		}
+	}
+	checkList(t, list, nil)
+	if list.blocks == nil || list.blocks.next != nil {
+		t.Errorf("Expected a single retained block after draining")
+	}
+	if list.capacity != cap(list.blocks.nodes) {
+		t.Errorf("Expected capacity %d after draining, got %d", cap(list.blocks.nodes), list.capacity)
+	}
+}
+
+// checkList verifies the list holds expected and that the block
+// bookkeeping agrees with the linked nodes.
+func checkList(t *testing.T, list *XLL[int], expected []int) {
+	t.Helper()
+	i := 0
+	err := list.TraverseForward(func(data int) {
+		if i >= len(expected) || data != expected[i] {
+			t.Fatalf("Unexpected element %d at position %d", data, i)
+		}
+		i++
+	})
+	if err != nil {
+		t.Fatalf("TraverseForward failed: %v", err)
+	}
+	if i != len(expected) || list.Size() != len(expected) {
+		t.Fatalf("Expected %d elements, traversed %d with size %d", len(expected), i, list.Size())
+	}
+
+	live, capacity := 0, 0
+	for block := list.blocks; block != nil; block = block.next {
+		if block.live == 0 && block != list.blocks {
+			t.Fatalf("Dead block of capacity %d was not released", cap(block.nodes))
+		}
+		dead := 0
+		for f := block.free; f != 0; f = int(block.nodes[f-1].both) {
+			dead++
+		}
+		if dead != len(block.nodes)-block.live {
+			t.Fatalf("Block has %d dead slots on its free list, expected %d", dead, len(block.nodes)-block.live)
+		}
+		live += block.live
+		capacity += cap(block.nodes)
+	}
+	if live != list.size {
+		t.Fatalf("Blocks hold %d live nodes, list size is %d", live, list.size)
+	}
+	if capacity != list.capacity {
+		t.Fatalf("Blocks hold %d slots, list capacity is %d", capacity, list.capacity)
+	}
+}
+
 func TestTraverse(t *testing.T) {
 	list := New[int]()
 	elements := []int{1, 2, 3, 4, 5}
@@ -110,28 +347,1322 @@ func TestTraverse(t *testing.T) {
 		}
 	}
 
-	// Test TraverseForward
-	i := 0
-	err := list.TraverseForward(func(data int) {
-		if data != elements[i] {
-			t.Errorf("TraverseForward: Expected %d at position %d, got %d", elements[i], i, data)
+	// Test TraverseForward
+	i := 0
+	err := list.TraverseForward(func(data int) {
+		if data != elements[i] {
+			t.Errorf("TraverseForward: Expected %d at position %d, got %d", elements[i], i, data)
+		}
+		i++
+	})
+	if err != nil {
+		t.Errorf("TraverseForward failed: %v", err)
+	}
+
+	// Test TraverseBackward
+	i = len(elements) - 1
+	err = list.TraverseBackward(func(data int) {
+		if data != elements[i] {
+			t.Errorf("TraverseBackward: Expected %d at position %d, got %d", elements[i], i, data)
+		}
+		i--
+	})
+	if err != nil {
+		t.Errorf("TraverseBackward failed: %v", err)
+	}
+}
+
+func TestIterators(t *testing.T) {
+	list := New[int]()
+	elements := []int{1, 2, 3, 4, 5}
+	for _, e := range elements {
+		if err := list.InsertBack(e); err != nil {
+			t.Fatalf("InsertBack failed: %v", err)
+		}
+	}
+
+	// Test Iterator starts at the front element
+	i := 0
+	for it := list.Iterator(); it.Next(); i++ {
+		if it.Value() != elements[i] {
+			t.Errorf("Iterator: Expected %d at position %d, got %d", elements[i], i, it.Value())
+		}
+	}
+	if i != len(elements) {
+		t.Errorf("Iterator: Expected %d elements, got %d", len(elements), i)
+	}
+
+	// Test All
+	i = 0
+	for v := range list.All() {
+		if v != elements[i] {
+			t.Errorf("All: Expected %d at position %d, got %d", elements[i], i, v)
+		}
+		i++
+	}
+	if i != len(elements) {
+		t.Errorf("All: Expected %d elements, got %d", len(elements), i)
+	}
+
+	// Test Backward
+	i = len(elements) - 1
+	for v := range list.Backward() {
+		if v != elements[i] {
+			t.Errorf("Backward: Expected %d at position %d, got %d", elements[i], i, v)
+		}
+		i--
+	}
+
+	// Test Enumerate
+	for i, v := range list.Enumerate() {
+		if v != elements[i] {
+			t.Errorf("Enumerate: Expected %d at position %d, got %d", elements[i], i, v)
+		}
+	}
+
+	// Test break releases the read lock
+	for v := range list.All() {
+		if v == 2 {
+			break
+		}
+	}
+	if err := list.InsertBack(6); err != nil {
+		t.Errorf("InsertBack after break failed: %v", err)
+	}
+
+	// Test ranging over a freed list
+	if err := list.Free(); err != nil {
+		t.Errorf("Free failed: %v", err)
+	}
+	defer func() {
+		if r := recover(); r != ErrFreedList {
+			t.Errorf("Expected panic with ErrFreedList, got %v", r)
+		}
+	}()
+	for range list.All() {
+		t.Errorf("All yielded an element of a freed list")
+	}
+}
+
+func TestCursor(t *testing.T) {
+	for name, options := range map[string][]Option[int]{
+		"pointer": {WithBlockSize[int](2)},
+		"index":   {WithBlockSize[int](2), WithIndexLinks[int]()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			list := New[int](options...)
+
+			// Test inserting through a cursor on an empty list
+			c := list.CursorFront()
+			if _, err := c.Value(); !errors.Is(err, ErrEmptyList) {
+				t.Errorf("Expected ErrEmptyList, got %v", err)
+			}
+			if err := c.InsertAfter(3); err != nil {
+				t.Fatalf("InsertAfter failed: %v", err)
+			}
+			if err := c.InsertBefore(1); err != nil {
+				t.Fatalf("InsertBefore failed: %v", err)
+			}
+			if err := c.InsertAfter(5); err != nil {
+				t.Fatalf("InsertAfter failed: %v", err)
+			}
+			expectElements(t, list, 1, 3, 5)
+
+			// Test moving in both directions
+			if v, err := c.Value(); err != nil || v != 3 {
+				t.Errorf("Expected cursor on 3, got %d (%v)", v, err)
+			}
+			if !c.Next() || c.Next() {
+				t.Errorf("Expected exactly one step towards the back")
+			}
+			if err := c.InsertBefore(4); err != nil {
+				t.Fatalf("InsertBefore failed: %v", err)
+			}
+			if !c.Prev() || !c.Prev() || !c.Prev() || c.Prev() {
+				t.Errorf("Expected exactly three steps towards the front")
+			}
+			if err := c.InsertAfter(2); err != nil {
+				t.Fatalf("InsertAfter failed: %v", err)
+			}
+			expectElements(t, list, 1, 2, 3, 4, 5)
+
+			// Test Set and Remove
+			if err := c.Set(10); err != nil {
+				t.Errorf("Set failed: %v", err)
+			}
+			if err := c.Remove(); err != nil {
+				t.Errorf("Remove failed: %v", err)
+			}
+			if v, _ := c.Value(); v != 2 {
+				t.Errorf("Expected cursor on 2 after removing the front, got %d", v)
+			}
+			c.Next()
+			if err := c.Remove(); err != nil {
+				t.Errorf("Remove failed: %v", err)
+			}
+			expectElements(t, list, 2, 4, 5)
+
+			back := list.CursorBack()
+			if err := back.Remove(); err != nil {
+				t.Errorf("Remove failed: %v", err)
+			}
+			if v, _ := back.Value(); v != 4 {
+				t.Errorf("Expected cursor on 4 after removing the back, got %d", v)
+			}
+			if !back.Prev() {
+				t.Errorf("Expected to step back to the front")
+			}
+			expectElements(t, list, 2, 4)
+			if list.Size() != 2 {
+				t.Errorf("Expected size 2, got %d", list.Size())
+			}
+
+			// Test staleness after modification through another path
+			if err := c.Set(0); !errors.Is(err, ErrStaleCursor) {
+				t.Errorf("Expected ErrStaleCursor, got %v", err)
+			}
+			if err := list.InsertBack(6); err != nil {
+				t.Fatalf("InsertBack failed: %v", err)
+			}
+			if back.Next() {
+				t.Errorf("Expected stale cursor not to move")
+			}
+			if _, err := back.Value(); !errors.Is(err, ErrStaleCursor) {
+				t.Errorf("Expected ErrStaleCursor, got %v", err)
+			}
+
+			// Test a loop over Next can tell the end from staleness
+			walk := list.CursorFront()
+			for walk.Next() {
+			}
+			if err := walk.Err(); err != nil {
+				t.Errorf("Expected no error at the end, got %v", err)
+			}
+			walk = list.CursorFront()
+			steps := 0
+			for walk.Next() {
+				if steps++; steps == 1 {
+					_ = list.InsertFront(8)
+				}
+			}
+			if steps != 1 || !errors.Is(walk.Err(), ErrStaleCursor) {
+				t.Errorf("Expected the walk to stop with ErrStaleCursor, got %v after %d steps", walk.Err(), steps)
+			}
+			for walk.Prev() {
+			}
+			if !errors.Is(walk.Err(), ErrStaleCursor) {
+				t.Errorf("Expected ErrStaleCursor from Prev, got %v", walk.Err())
+			}
+			_, _ = list.PopFront()
+
+			// Test removing every element
+			c = list.CursorFront()
+			for list.Size() > 0 {
+				if err := c.Remove(); err != nil {
+					t.Fatalf("Remove failed: %v", err)
+				}
+			}
+			if err := c.Remove(); !errors.Is(err, ErrEmptyList) {
+				t.Errorf("Expected ErrEmptyList, got %v", err)
+			}
+			expectElements(t, list)
+
+			if err := list.Free(); err != nil {
+				t.Errorf("Free failed: %v", err)
+			}
+			if err := c.InsertAfter(1); !errors.Is(err, ErrFreedList) {
+				t.Errorf("Expected ErrFreedList, got %v", err)
+			}
+			if c.Next() || !errors.Is(c.Err(), ErrFreedList) {
+				t.Errorf("Expected Next to stop with ErrFreedList, got %v", c.Err())
+			}
+		})
+	}
+}
+
+func TestPositional(t *testing.T) {
+	list := New[int](WithBlockSize[int](4))
+	var expected []int
+	for i := 0; i < 9; i++ {
+		if err := list.InsertAt(i/2, i); err != nil {
+			t.Fatalf("InsertAt(%d) failed: %v", i/2, err)
+		}
+		expected = slices.Insert(expected, i/2, i)
+	}
+	expectElements(t, list, expected...)
+
+	// Test At from both halves
+	for i, want := range expected {
+		if v, err := list.At(i); err != nil || v != want {
+			t.Errorf("At(%d): Expected %d, got %d (%v)", i, want, v, err)
+		}
+	}
+
+	// Test Set
+	if err := list.Set(7, 70); err != nil {
+		t.Errorf("Set failed: %v", err)
+	}
+	expected[7] = 70
+	expectElements(t, list, expected...)
+
+	// Test InsertAt the back
+	if err := list.InsertAt(list.Size(), 100); err != nil {
+		t.Errorf("InsertAt failed: %v", err)
+	}
+	expected = append(expected, 100)
+
+	// Test RemoveAt front, middle and back
+	for _, i := range []int{0, 4, 6, 2} {
+		v, err := list.RemoveAt(i)
+		if err != nil || v != expected[i] {
+			t.Errorf("RemoveAt(%d): Expected %d, got %d (%v)", i, expected[i], v, err)
+		}
+		expected = slices.Delete(expected, i, i+1)
+		expectElements(t, list, expected...)
+	}
+	if v, err := list.RemoveAt(list.Size() - 1); err != nil || v != 100 {
+		t.Errorf("RemoveAt(back): Expected 100, got %d (%v)", v, err)
+	}
+	expected = expected[:len(expected)-1]
+	expectElements(t, list, expected...)
+
+	// Test bounds
+	var indexErr *IndexError
+	if _, err := list.At(len(expected)); !errors.As(err, &indexErr) || indexErr.Index != len(expected) {
+		t.Errorf("Expected IndexError, got %v", err)
+	}
+	if err := list.Set(-1, 0); !errors.As(err, &indexErr) {
+		t.Errorf("Expected IndexError, got %v", err)
+	}
+	if err := list.InsertAt(len(expected)+1, 0); !errors.As(err, &indexErr) {
+		t.Errorf("Expected IndexError, got %v", err)
+	}
+	if _, err := New[int]().RemoveAt(0); !errors.As(err, &indexErr) || indexErr.Size != 0 {
+		t.Errorf("Expected IndexError, got %v", err)
+	}
+}
+
+func TestReverse(t *testing.T) {
+	list := New[int]()
+	for _, v := range []int{1, 2, 3, 4} {
+		if err := list.InsertBack(v); err != nil {
+			t.Fatalf("InsertBack failed: %v", err)
+		}
+	}
+	c := list.CursorFront()
+	view := list.Reversed()
+
+	// Test the view leaves the list alone
+	if got := slices.Collect(view.All()); !slices.Equal(got, []int{4, 3, 2, 1}) {
+		t.Errorf("Expected reversed view [4 3 2 1], got %v", got)
+	}
+	if v, err := view.At(0); err != nil || v != 4 {
+		t.Errorf("Expected view.At(0) to be 4, got %d (%v)", v, err)
+	}
+	for i, v := range view.Enumerate() {
+		if v != 4-i {
+			t.Errorf("Expected %d at view position %d, got %d", 4-i, i, v)
+		}
+	}
+	expectElements(t, list, 1, 2, 3, 4)
+
+	// Test Reverse
+	if err := list.Reverse(); err != nil {
+		t.Fatalf("Reverse failed: %v", err)
+	}
+	expectElements(t, list, 4, 3, 2, 1)
+	if v, _ := list.PeekFront(); v != 4 {
+		t.Errorf("Expected front 4 after Reverse, got %d", v)
+	}
+	if v, _ := view.PeekFront(); v != 1 {
+		t.Errorf("Expected view front 1 after Reverse, got %d", v)
+	}
+	if err := c.Set(0); !errors.Is(err, ErrStaleCursor) {
+		t.Errorf("Expected ErrStaleCursor after Reverse, got %v", err)
+	}
+
+	// Test operations after Reverse follow the new order
+	c = list.CursorFront()
+	c.Next()
+	if err := c.InsertAfter(25); err != nil {
+		t.Errorf("InsertAfter failed: %v", err)
+	}
+	if err := list.InsertFront(5); err != nil {
+		t.Errorf("InsertFront failed: %v", err)
+	}
+	if v, err := list.PopBack(); err != nil || v != 1 {
+		t.Errorf("Expected PopBack to return 1, got %d (%v)", v, err)
+	}
+	expectElements(t, list, 5, 4, 3, 25, 2)
+	i := 0
+	for it := list.Iterator(); it.Next(); i++ {
+		if want := []int{5, 4, 3, 25, 2}[i]; it.Value() != want {
+			t.Errorf("Iterator: Expected %d at position %d, got %d", want, i, it.Value())
+		}
+	}
+
+	// Test reversing twice restores the order
+	if err := list.Reverse(); err != nil {
+		t.Fatalf("Reverse failed: %v", err)
+	}
+	if err := list.Reverse(); err != nil {
+		t.Fatalf("Reverse failed: %v", err)
+	}
+	expectElements(t, list, 5, 4, 3, 25, 2)
+}
+
+func TestAppendPrepend(t *testing.T) {
+	newList := func(options []Option[int], values ...int) *XLL[int] {
+		list := New[int](options...)
+		for _, v := range values {
+			if err := list.InsertBack(v); err != nil {
+				t.Fatalf("InsertBack failed: %v", err)
+			}
+		}
+		return list
+	}
+	small := []Option[int]{WithBlockSize[int](2)}
+	indexed := []Option[int]{WithBlockSize[int](2), WithIndexLinks[int]()}
+
+	for name, otherOptions := range map[string][]Option[int]{"pointer": small, "index": indexed} {
+		t.Run(name, func(t *testing.T) {
+			list := newList(small, 3, 4)
+			other := newList(otherOptions, 5, 6, 7)
+
+			// Test Append
+			if err := list.Append(other); err != nil {
+				t.Fatalf("Append failed: %v", err)
+			}
+			expectElements(t, list, 3, 4, 5, 6, 7)
+			expectElements(t, other)
+			if other.Size() != 0 {
+				t.Errorf("Expected other to be empty, got size %d", other.Size())
+			}
+
+			// Test other is reusable and Prepend
+			for _, v := range []int{1, 2} {
+				if err := other.InsertBack(v); err != nil {
+					t.Fatalf("InsertBack failed: %v", err)
+				}
+			}
+			if err := list.Prepend(other); err != nil {
+				t.Fatalf("Prepend failed: %v", err)
+			}
+			expectElements(t, list, 1, 2, 3, 4, 5, 6, 7)
+			checkList(t, list, []int{1, 2, 3, 4, 5, 6, 7})
+
+			// Test freeing other leaves the adopted nodes intact
+			if err := other.Free(); err != nil {
+				t.Errorf("Free failed: %v", err)
+			}
+			runtime.GC()
+			for _, v := range []int{8, 9, 10} {
+				if err := list.InsertBack(v); err != nil {
+					t.Fatalf("InsertBack failed: %v", err)
+				}
+			}
+			for list.Size() > 4 {
+				if err := list.DeleteFront(); err != nil {
+					t.Fatalf("DeleteFront failed: %v", err)
+				}
+			}
+			checkList(t, list, []int{7, 8, 9, 10})
+		})
+	}
+
+	// Test empty lists on either side
+	list := New[int]()
+	if err := list.Append(newList(nil)); err != nil {
+		t.Errorf("Append of empty list failed: %v", err)
+	}
+	if err := list.Prepend(newList(nil, 1, 2)); err != nil {
+		t.Errorf("Prepend into empty list failed: %v", err)
+	}
+	expectElements(t, list, 1, 2)
+
+	// Test errors
+	if err := list.Append(list); !errors.Is(err, ErrSameList) {
+		t.Errorf("Expected ErrSameList, got %v", err)
+	}
+	freed := newList(nil, 1)
+	if err := freed.Free(); err != nil {
+		t.Errorf("Free failed: %v", err)
+	}
+	if err := list.Append(freed); !errors.Is(err, ErrFreedList) {
+		t.Errorf("Expected ErrFreedList, got %v", err)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	for name, options := range map[string][]Option[int]{
+		"pointer": {WithBlockSize[int](3)},
+		"index":   {WithBlockSize[int](3), WithIndexLinks[int]()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for _, at := range []int{0, 1, 4, 7, 9, 10} {
+				list := New[int](options...)
+				var expected []int
+				for i := 0; i < 10; i++ {
+					if err := list.InsertBack(i); err != nil {
+						t.Fatalf("InsertBack failed: %v", err)
+					}
+					expected = append(expected, i)
+				}
+
+				rest, err := list.SplitAt(at)
+				if err != nil {
+					t.Fatalf("SplitAt(%d) failed: %v", at, err)
+				}
+				checkList(t, list, expected[:at])
+				checkList(t, rest, expected[at:])
+
+				// Test freeing one half leaves the other usable
+				if err := list.Free(); err != nil {
+					t.Errorf("Free failed: %v", err)
+				}
+				runtime.GC()
+				if err := rest.InsertFront(-1); err != nil {
+					t.Fatalf("InsertFront failed: %v", err)
+				}
+				checkList(t, rest, append([]int{-1}, expected[at:]...))
+				if err := rest.Free(); err != nil {
+					t.Errorf("Free failed: %v", err)
+				}
+			}
+
+			// Test splitting at a cursor
+			list := New[int](options...)
+			for i := 0; i < 6; i++ {
+				if err := list.InsertBack(i); err != nil {
+					t.Fatalf("InsertBack failed: %v", err)
+				}
+			}
+			c := list.CursorBack()
+			c.Prev()
+			rest, err := c.Split()
+			if err != nil {
+				t.Fatalf("Split failed: %v", err)
+			}
+			checkList(t, list, []int{0, 1, 2, 3})
+			checkList(t, rest, []int{4, 5})
+			if _, err := c.Split(); !errors.Is(err, ErrStaleCursor) {
+				t.Errorf("Expected ErrStaleCursor, got %v", err)
+			}
+			c = list.CursorFront()
+			c.Next()
+			if rest, err = c.Split(); err != nil {
+				t.Fatalf("Split failed: %v", err)
+			}
+			checkList(t, list, []int{0})
+			checkList(t, rest, []int{1, 2, 3})
+		})
+	}
+
+	// Test bounds
+	var indexErr *IndexError
+	if _, err := New[int]().SplitAt(1); !errors.As(err, &indexErr) {
+		t.Errorf("Expected IndexError, got %v", err)
+	}
+}
+
+func TestClone(t *testing.T) {
+	list := New[int](WithBlockSize[int](4), WithGrowthRate[int](3), WithMaxSize[int](100, OverflowDropOldest))
+	for i := 0; i < 50; i++ {
+		_ = list.InsertBack(i)
+	}
+	for i := 0; i < 20; i++ {
+		_ = list.DeleteFront()
+	}
+	expected, _ := list.ToSlice()
+
+	clone, err := list.Clone()
+	if err != nil {
+		t.Fatalf("Clone failed: %v", err)
+	}
+	checkList(t, clone, expected)
+	if clone.blocks == nil || clone.blocks.next != nil || cap(clone.blocks.nodes) != len(expected) {
+		t.Errorf("Expected a single block of %d nodes", len(expected))
+	}
+	if clone.blockSize != 4 || clone.growthRate != 3 || clone.maxSize != 100 || clone.policy != OverflowDropOldest {
+		t.Errorf("Expected the clone to keep the list's options")
+	}
+
+	// Test the copies are independent
+	_ = list.InsertBack(-1)
+	_ = clone.DeleteBack()
+	checkList(t, list, append(slices.Clone(expected), -1))
+	checkList(t, clone, expected[:len(expected)-1])
+
+	// Test cloning in index mode and of an empty list
+	indexed := FromSlice([]int{1, 2, 3}, WithIndexLinks[int]())
+	clone, _ = indexed.Clone()
+	if !clone.indexed {
+		t.Errorf("Expected the clone to use index links")
+	}
+	expectElements(t, clone, 1, 2, 3)
+	clone, _ = New[int]().Clone()
+	expectElements(t, clone)
+
+	// Test CloneFunc copies what elements point to
+	pointers := New[*int]()
+	for i := 0; i < 3; i++ {
+		_ = pointers.InsertBack(&i)
+	}
+	deep, err := pointers.CloneFunc(func(p *int) *int {
+		v := *p
+		return &v
+	})
+	if err != nil {
+		t.Fatalf("CloneFunc failed: %v", err)
+	}
+	shallow, _ := pointers.Clone()
+	for p := range pointers.All() {
+		*p += 10
+	}
+	var got []int
+	for p := range deep.All() {
+		got = append(got, *p)
+	}
+	if !slices.Equal(got, []int{0, 1, 2}) {
+		t.Errorf("Expected the deep copy to keep [0 1 2], got %v", got)
+	}
+	if p, _ := shallow.PeekFront(); *p != 10 {
+		t.Errorf("Expected the shallow copy to share elements, got %d", *p)
+	}
+
+	_ = list.Free()
+	if _, err := list.Clone(); !errors.Is(err, ErrFreedList) {
+		t.Errorf("Expected ErrFreedList, got %v", err)
+	}
+}
+
+func TestBinary(t *testing.T) {
+	list := New[int](WithBlockSize[int](8), WithGrowthRate[int](1.5))
+	for i := 0; i < 100; i++ {
+		_ = list.InsertBack(i * i)
+	}
+	expected, _ := list.ToSlice()
+	data, err := list.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	// Test decoding into a new list and into a zero value
+	decoded := New[int](WithIndexLinks[int]())
+	_ = decoded.InsertBack(-1)
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	checkList(t, decoded, expected)
+	if decoded.blockSize != 8 || decoded.growthRate != 1.5 {
+		t.Errorf("Expected block size 8 and growth rate 1.5, got %d and %v", decoded.blockSize, decoded.growthRate)
+	}
+	var zero XLL[int]
+	if err := zero.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	_ = zero.InsertBack(-1)
+	checkList(t, &zero, append(slices.Clone(expected), -1))
+
+	// Test WriteTo and ReadFrom stop at the end of one encoding
+	var buf bytes.Buffer
+	words := FromSlice([]string{"", "xor", "linked list"})
+	n1, err1 := words.WriteTo(&buf)
+	n2, err2 := New[string]().WriteTo(&buf)
+	if err1 != nil || err2 != nil || n1+n2 != int64(buf.Len()) {
+		t.Fatalf("WriteTo wrote %d and %d of %d bytes: %v, %v", n1, n2, buf.Len(), err1, err2)
+	}
+	first, second := New[string](), FromSlice([]string{"old"})
+	if n, err := first.ReadFrom(&buf); err != nil || n != n1 {
+		t.Fatalf("ReadFrom read %d of %d bytes: %v", n, n1, err)
+	}
+	if _, err := second.ReadFrom(&buf); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	if got, _ := first.ToSlice(); !slices.Equal(got, []string{"", "xor", "linked list"}) {
+		t.Errorf("Expected the strings back, got %q", got)
+	}
+	if second.Size() != 0 {
+		t.Errorf("Expected an empty list, got size %d", second.Size())
+	}
+
+	// Test fixed-size types and custom codecs
+	type point struct{ X, Y float32 }
+	points := FromSlice([]point{{1, 2}, {3, 4}})
+	data, _ = points.MarshalBinary()
+	points = New[point]()
+	if err := points.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if p, _ := points.PeekBack(); p != (point{3, 4}) {
+		t.Errorf("Expected {3 4}, got %v", p)
+	}
+	if _, err := New[named]().MarshalBinary(); !errors.Is(err, ErrNoCodec) {
+		t.Errorf("Expected ErrNoCodec, got %v", err)
+	}
+	names := FromSlice([]named{{"a"}, {"bc"}}, WithCodec[named](namedCodec{}))
+	data, err = names.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	names = New[named](WithCodec[named](namedCodec{}))
+	if err := names.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if got, _ := names.ToSlice(); !slices.Equal(got, []named{{"a"}, {"bc"}}) {
+		t.Errorf("Expected [{a} {bc}], got %v", got)
+	}
+
+	// Test corrupt input fails without touching the list
+	data, _ = FromSlice([]int{1, 2, 3}).MarshalBinary()
+	target := FromSlice([]int{7})
+	for name, bad := range map[string][]byte{
+		"flipped bit": func() []byte {
+			b := slices.Clone(data)
+			b[len(b)-10] ^= 1
+			return b
+		}(),
+		"truncated":  data[:len(data)-1],
+		"no header":  data[:3],
+		"bad magic":  append([]byte("XXXX"), data[4:]...),
+		"trailing":   append(slices.Clone(data), 0),
+		"huge count": binary.LittleEndian.AppendUint64(slices.Clone(data[:6]), 1<<62),
+	} {
+		if err := target.UnmarshalBinary(bad); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("Expected ErrCorrupt for %s, got %v", name, err)
+		}
+		expectElements(t, target, 7)
+	}
+
+	_ = list.Free()
+	if _, err := list.MarshalBinary(); !errors.Is(err, ErrFreedList) {
+		t.Errorf("Expected ErrFreedList, got %v", err)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	list := FromSlice([]int{3, 1, 2})
+	data, err := json.Marshal(list)
+	if err != nil || string(data) != "[3,1,2]" {
+		t.Errorf("Expected [3,1,2], got %s (%v)", data, err)
+	}
+	data, _ = json.Marshal(New[string]())
+	if string(data) != "[]" {
+		t.Errorf("Expected [], got %s", data)
+	}
+
+	// Test lists inside other values, decoded into zero values
+	type message struct {
+		Tags *XLL[string] `json:"tags"`
+	}
+	data, err = json.Marshal(message{Tags: FromSlice([]string{"a", "<b>", `"c"`})})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var m message
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	_ = m.Tags.InsertBack("d")
+	if got, _ := m.Tags.ToSlice(); !slices.Equal(got, []string{"a", "<b>", `"c"`, "d"}) {
+		t.Errorf("Expected the tags back, got %q", got)
+	}
+
+	// Test decoding keeps the list's options
+	list = New[int](WithMaxSize[int](2, OverflowDropOldest), WithBlockSize[int](4))
+	if err := json.Unmarshal([]byte(" [1, 2, 3] "), list); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	expectElements(t, list, 2, 3)
+	if list.blockSize != 4 || list.maxSize != 2 {
+		t.Errorf("Expected the options to survive decoding")
+	}
+
+	// Test null and malformed input
+	if err := json.Unmarshal([]byte("null"), list); err != nil {
+		t.Errorf("Expected null to be ignored, got %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"a": 1}`), list); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Expected ErrCorrupt, got %v", err)
+	}
+	if err := json.Unmarshal([]byte(`[1, "two"]`), list); err == nil {
+		t.Errorf("Expected an error decoding a string as an int")
+	}
+	if err := list.UnmarshalJSON([]byte("[1,2] garbage")); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Expected ErrCorrupt for trailing data, got %v", err)
+	}
+	if err := list.UnmarshalJSON([]byte("null [1]")); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Expected ErrCorrupt for trailing data, got %v", err)
+	}
+	expectElements(t, list, 2, 3)
+
+	// Test ReadJSON leaves what follows the array unread
+	r := strings.NewReader(`[4, 5] {"next": 1}`)
+	if err := list.ReadJSON(r); err != nil {
+		t.Errorf("ReadJSON failed: %v", err)
+	}
+	expectElements(t, list, 4, 5)
+
+	_ = list.Free()
+	if _, err := json.Marshal(list); !errors.Is(err, ErrFreedList) {
+		t.Errorf("Expected ErrFreedList, got %v", err)
+	}
+}
+
+func TestGob(t *testing.T) {
+	// Test lists of structs travel inside other values
+	type task struct {
+		ID   int
+		Name string
+		Tags []string
+	}
+	type batch struct {
+		Tasks *XLL[task]
+		Words *XLL[string]
+	}
+	in := batch{
+		Tasks: FromSlice([]task{{1, "parse", nil}, {2, "link", []string{"xor"}}}, WithBlockSize[task](16), WithGrowthRate[task](1.25)),
+		Words: FromSlice([]string{"b", "a", ""}),
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	var out batch
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	tasks, _ := out.Tasks.ToSlice()
+	if len(tasks) != 2 || tasks[0].Name != "parse" || tasks[1].ID != 2 || !slices.Equal(tasks[1].Tags, []string{"xor"}) {
+		t.Errorf("Expected the tasks back, got %v", tasks)
+	}
+	if out.Tasks.blockSize != 16 || out.Tasks.growthRate != 1.25 {
+		t.Errorf("Expected block size 16 and growth rate 1.25, got %d and %v", out.Tasks.blockSize, out.Tasks.growthRate)
+	}
+	if words, _ := out.Words.ToSlice(); !slices.Equal(words, []string{"b", "a", ""}) {
+		t.Errorf("Expected [b a ], got %q", words)
+	}
+	_ = out.Words.InsertFront("c")
+	expectedWords := []string{"c", "b", "a", ""}
+	if words, _ := out.Words.ToSlice(); !slices.Equal(words, expectedWords) {
+		t.Errorf("Expected %q, got %q", expectedWords, words)
+	}
+
+	// Test pointer elements are decoded into fresh values
+	values := []int{4, 5, 6}
+	pointers := New[*int]()
+	for i := range values {
+		_ = pointers.InsertBack(&values[i])
+	}
+	data, err := pointers.GobEncode()
+	if err != nil {
+		t.Fatalf("GobEncode failed: %v", err)
+	}
+	decoded := New[*int]()
+	if err := decoded.GobDecode(data); err != nil {
+		t.Fatalf("GobDecode failed: %v", err)
+	}
+	values[0] = 0
+	var got []int
+	for p := range decoded.All() {
+		got = append(got, *p)
+	}
+	if !slices.Equal(got, []int{4, 5, 6}) {
+		t.Errorf("Expected [4 5 6], got %v", got)
+	}
+
+	// Test malformed input leaves the list alone
+	if err := decoded.GobDecode(data[:len(data)/2]); err == nil {
+		t.Errorf("Expected an error decoding truncated input")
+	}
+	if len(got) != decoded.Size() {
+		t.Errorf("Expected the list to be unchanged")
+	}
+	_ = pointers.Free()
+	if _, err := pointers.GobEncode(); !errors.Is(err, ErrFreedList) {
+		t.Errorf("Expected ErrFreedList, got %v", err)
+	}
+}
+
+type named struct{ Name string }
+
+// namedCodec encodes a named as its name followed by a zero byte.
+type namedCodec struct{}
+
+func (namedCodec) Encode(w io.Writer, v named) error {
+	_, err := io.WriteString(w, v.Name+"\x00")
+	return err
+}
+
+func (namedCodec) Decode(r io.Reader) (named, error) {
+	var name []byte
+	for b := make([]byte, 1); ; {
+		if _, err := io.ReadFull(r, b); err != nil {
+			return named{}, err
 		}
-		i++
-	})
+		if b[0] == 0 {
+			return named{Name: string(name)}, nil
+		}
+		name = append(name, b[0])
+	}
+}
+
+func TestOpenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "list.xll")
+	list, err := OpenFile[int](path, WithBlockSize[int](16))
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("memory-mapped files are not supported on this platform")
+	}
 	if err != nil {
-		t.Errorf("TraverseForward failed: %v", err)
+		t.Fatalf("OpenFile failed: %v", err)
 	}
+	exerciseBlocks(t, list)
 
-	// Test TraverseBackward
-	i = len(elements) - 1
-	err = list.TraverseBackward(func(data int) {
-		if data != elements[i] {
-			t.Errorf("TraverseBackward: Expected %d at position %d, got %d", elements[i], i, data)
-		}
-		i--
+	// Test the contents survive closing and reopening, growing the file
+	// along the way
+	for i := 0; i < 1000; i++ {
+		_ = list.InsertFront(i)
+	}
+	_, _ = list.RemoveAt(3)
+	expected, _ := list.ToSlice()
+	if err := list.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := list.InsertBack(1); !errors.Is(err, ErrFreedList) {
+		t.Errorf("Expected ErrFreedList, got %v", err)
+	}
+	list, err = OpenFile[int](path)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v", err)
+	}
+	checkList(t, list, expected)
+
+	// Test splitting keeps the front half in the file
+	rest, err := list.SplitAt(10)
+	if err != nil {
+		t.Fatalf("SplitAt failed: %v", err)
+	}
+	checkList(t, rest, expected[10:])
+	_ = list.InsertBack(-1)
+	if err := list.Sync(); err != nil {
+		t.Errorf("Sync failed: %v", err)
+	}
+	if err := list.Free(); err != nil {
+		t.Errorf("Free failed: %v", err)
+	}
+	list, _ = OpenFile[int](path)
+	checkList(t, list, append(slices.Clone(expected[:10]), -1))
+
+	// Test replacing the contents keeps the list in the file
+	data, _ := FromSlice([]int{1, 2, 3}).MarshalBinary()
+	if err := list.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	_ = list.Close()
+	list, _ = OpenFile[int](path)
+	expectElements(t, list, 1, 2, 3)
+
+	// Test splitting off everything leaves an empty list in the file, by
+	// position and from a cursor at the front
+	rest, _ = list.SplitAt(0)
+	expectElements(t, rest, 1, 2, 3)
+	checkList(t, list, nil)
+	_ = list.InsertBack(9)
+	_ = list.InsertBackAll(10, 11)
+	rest, _ = list.CursorFront().Split()
+	expectElements(t, rest, 9, 10, 11)
+	checkList(t, list, nil)
+	_ = list.InsertBack(12)
+	_ = list.Close()
+	list, _ = OpenFile[int](path)
+	expectElements(t, list, 12)
+	_ = list.Close()
+
+	// Test insertions the file cannot grow for fail, leaving the list and
+	// its log unchanged
+	var log bytes.Buffer
+	full := filepath.Join(t.TempDir(), "full.xll")
+	list, err = OpenFile[int](full, WithBlockSize[int](4), WithWAL[int](&log, nil))
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v", err)
+	}
+	_ = list.InsertBackAll(0, 1, 2, 3)
+	list.file.f.Close()
+	if err := list.InsertBack(4); err == nil {
+		t.Errorf("Expected InsertBack to fail when the file cannot grow")
+	}
+	if err := list.InsertFront(4); err == nil {
+		t.Errorf("Expected InsertFront to fail when the file cannot grow")
+	}
+	if err := list.InsertAt(2, 4); err == nil {
+		t.Errorf("Expected InsertAt to fail when the file cannot grow")
+	}
+	if err := list.InsertBackAll(4, 5); err == nil {
+		t.Errorf("Expected InsertBackAll to fail when the file cannot grow")
+	}
+	if err := list.CursorFront().InsertAfter(4); err == nil {
+		t.Errorf("Expected InsertAfter to fail when the file cannot grow")
+	}
+	data, _ = FromSlice([]int{5, 6, 7, 8, 9}).MarshalBinary()
+	if err := list.UnmarshalBinary(data); err == nil {
+		t.Errorf("Expected UnmarshalBinary to fail when the file cannot grow")
+	}
+	checkList(t, list, []int{0, 1, 2, 3})
+	recovered, err := Recover[int](bytes.NewReader(log.Bytes()))
+	if err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+	checkList(t, recovered, []int{0, 1, 2, 3})
+	_ = list.Free()
+
+	// Test element types and files that do not fit
+	type record struct {
+		ID    uint64
+		Score [2]float32
+	}
+	if _, err := OpenFile[record](path); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Expected ErrCorrupt for a different node size, got %v", err)
+	}
+	if _, err := OpenFile[string](path); !errors.Is(err, ErrPointerElements) {
+		t.Errorf("Expected ErrPointerElements, got %v", err)
+	}
+	if _, err := OpenFile[struct{ P *int }](path); !errors.Is(err, ErrPointerElements) {
+		t.Errorf("Expected ErrPointerElements, got %v", err)
+	}
+	bad := filepath.Join(t.TempDir(), "bad.xll")
+	_ = os.WriteFile(bad, bytes.Repeat([]byte{1}, fileHeaderSize+16), 0o644)
+	if _, err := OpenFile[int](bad); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Expected ErrCorrupt, got %v", err)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	list := New[int](WithBlockSize[int](16))
+	for i := 0; i < 200; i++ {
+		_ = list.InsertBack(i)
+	}
+	original, _ := list.ToSlice()
+	view := list.Snapshot()
+	other := list.Snapshot()
+
+	// Test the view reads the shared nodes before any modification
+	if got := view.ToSlice(); !slices.Equal(got, original) {
+		t.Errorf("Expected the view to hold the list's elements, got %v", got)
+	}
+
+	// Test modifications leave the views untouched
+	_ = list.DeleteFront()
+	_ = list.InsertBack(-1)
+	_ = list.Reverse()
+	if list.epoch != nil {
+		t.Errorf("Expected the epoch to be copied out")
+	}
+	if view.Size() != 200 || !slices.Equal(view.ToSlice(), original) {
+		t.Errorf("Expected the view to keep its elements, got %v", view.ToSlice())
+	}
+	var backward []int
+	view.TraverseBackward(func(v int) {
+		backward = append(backward, v)
 	})
+	slices.Reverse(backward)
+	if !slices.Equal(backward, original) {
+		t.Errorf("Expected the view backwards, got %v", backward)
+	}
+	other.Release()
+	if other.Size() != 0 || len(other.ToSlice()) != 0 {
+		t.Errorf("Expected a released view to be empty")
+	}
+
+	// Test releasing every view spares writers the copy
+	view.Release()
+	view = list.Snapshot()
+	view.Release()
+	view.Release()
+	if list.epoch != nil {
+		t.Errorf("Expected no epoch once its views are released")
+	}
+
+	// Test a view survives Free and a snapshot of a freed list is empty
+	view = list.Snapshot()
+	expected, _ := list.ToSlice()
+	_ = list.Free()
+	if !slices.Equal(view.ToSlice(), expected) {
+		t.Errorf("Expected the view to survive Free, got %v", view.ToSlice())
+	}
+	if list.Snapshot().Size() != 0 {
+		t.Errorf("Expected an empty snapshot of a freed list")
+	}
+}
+
+// Test views can be read while writers continue
+func TestSnapshotConcurrent(t *testing.T) {
+	list := New[int](WithBlockSize[int](32))
+	for i := 0; i < 1000; i++ {
+		_ = list.InsertBack(i)
+	}
+	var wg, writer sync.WaitGroup
+	var done atomic.Bool
+
+	writer.Add(1)
+	go func() {
+		defer writer.Done()
+		for i := 0; !done.Load(); i++ {
+			_ = list.InsertBack(i)
+			_ = list.DeleteFront()
+		}
+	}()
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := 0; k < 20; k++ {
+				view := list.Snapshot()
+				expected := -1
+				n := 0
+				for v := range view.All() {
+					// The writer appends counting up from 0 while
+					// deleting the front, so any consistent view counts
+					// up by one except where the appended run starts.
+					if expected >= 0 && v != expected && v != 0 {
+						t.Errorf("Inconsistent view: %d after %d", v, expected-1)
+						break
+					}
+					expected = v + 1
+					n++
+				}
+				// The view may fall between the writer's two calls
+				if n != view.Size() || n < 1000 || n > 1001 {
+					t.Errorf("Expected 1000 or 1001 elements in the view, got %d of %d", n, view.Size())
+				}
+				view.Release()
+			}
+		}()
+	}
+	wg.Wait()
+	done.Store(true)
+	writer.Wait()
+}
+
+func TestWAL(t *testing.T) {
+	var log bytes.Buffer
+	list := New(WithWAL[int](&log, nil), WithBlockSize[int](4))
+	for i := 0; i < 10; i++ {
+		_ = list.InsertBack(i)
+	}
+	_ = list.InsertFront(-1)
+	_ = list.DeleteBack()
+	_, _ = list.PopFront()
+	_, _ = list.PopBackWait(context.Background())
+
+	// Test other changes are logged as snapshots
+	_ = list.InsertAt(2, 100)
+	_ = list.Reverse()
+	_ = list.InsertBack(200)
+	expected, _ := list.ToSlice()
+
+	recovered, err := Recover[int](bytes.NewReader(log.Bytes()))
 	if err != nil {
-		t.Errorf("TraverseBackward failed: %v", err)
+		t.Fatalf("Recover failed: %v", err)
+	}
+	checkList(t, recovered, expected)
+
+	// Test a record cut short by a crash returns what came before it and
+	// where it starts
+	logged := log.Len()
+	_ = list.InsertBack(300)
+	torn := log.Bytes()[:log.Len()-2]
+	recovered, err = Recover[int](bytes.NewReader(torn))
+	var walErr *WALError
+	if !errors.As(err, &walErr) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Expected a WALError for a torn record, got %v", err)
+	}
+	if walErr.Offset != int64(logged) {
+		t.Errorf("Expected offset %d, got %d", logged, walErr.Offset)
+	}
+	checkList(t, recovered, expected)
+
+	// Test a damaged record returns what came before it
+	damaged := slices.Clone(log.Bytes())
+	damaged[logged+5] ^= 0xff
+	recovered, err = Recover[int](bytes.NewReader(damaged))
+	if !errors.As(err, &walErr) || !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Expected ErrCorrupt, got %v", err)
+	}
+	checkList(t, recovered, expected)
+
+	// Test a list recovered from a torn log does not log after the torn
+	// record, and logs soundly again once compacted onto the log cut back
+	// to its good records
+	file := bytes.NewBuffer(slices.Clone(torn))
+	recovered, err = Recover(bytes.NewReader(file.Bytes()), WithWAL[int](file, nil))
+	if !errors.As(err, &walErr) {
+		t.Fatalf("Expected a WALError, got %v", err)
+	}
+	if err := recovered.InsertBack(100); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected the torn log to refuse records, got %v", err)
+	}
+	file.Truncate(int(walErr.Offset))
+	if err := recovered.Compact(file); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	_ = recovered.InsertBack(100)
+	_ = recovered.InsertBack(101)
+	recovered, err = Recover[int](bytes.NewReader(file.Bytes()))
+	if err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+	checkList(t, recovered, append(slices.Clone(expected), 100, 101))
+
+	// Test a damaged length running past the end of the log is reported
+	swallowed := slices.Clone(log.Bytes())
+	swallowed[logged+1] = 0xff
+	if _, err := Recover[int](bytes.NewReader(swallowed)); !errors.As(err, &walErr) || walErr.Offset != int64(logged) {
+		t.Errorf("Expected a WALError at offset %d, got %v", logged, err)
+	}
+
+	// Test a failed write leaves the list unchanged
+	list = New(WithWAL[int](failingWriter{}, nil))
+	if err := list.InsertBack(1); err == nil {
+		t.Errorf("Expected the write error")
+	}
+	if _, err := list.PopFront(); !errors.Is(err, ErrEmptyList) {
+		t.Errorf("Expected ErrEmptyList, got %v", err)
+	}
+	checkList(t, list, nil)
+
+	// Test replaying applies the overflow policy as the list did
+	log.Reset()
+	bounded := WithMaxSize[int](3, OverflowDropOldest)
+	list = New(WithWAL[int](&log, nil), bounded)
+	for i := 0; i < 5; i++ {
+		_ = list.InsertFront(i)
+	}
+	recovered, _ = Recover[int](bytes.NewReader(log.Bytes()), bounded)
+	checkList(t, recovered, []int{4, 3, 2})
+
+	// Test compaction rotates to a new log starting with a snapshot, and
+	// that a recovered list starts its log the same way
+	var logs []*bytes.Buffer
+	next := func() (io.Writer, error) {
+		logs = append(logs, new(bytes.Buffer))
+		return logs[len(logs)-1], nil
+	}
+	first, _ := next()
+	names := New(WithWAL(first, Codec[named](namedCodec{})), WithCompaction[named](5, next))
+	for i := 0; i < 12; i++ {
+		_ = names.InsertBack(named{Name: string(rune('a' + i))})
+	}
+	if len(logs) != 3 {
+		t.Fatalf("Expected 3 logs, got %d", len(logs))
+	}
+	var again bytes.Buffer
+	restored, err := Recover(bytes.NewReader(logs[2].Bytes()), WithWAL(io.Writer(&again), Codec[named](namedCodec{})))
+	if err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+	want, _ := names.ToSlice()
+	got, _ := restored.ToSlice()
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	_ = restored.DeleteFront()
+	restored, _ = Recover(bytes.NewReader(again.Bytes()), WithCodec[named](namedCodec{}))
+	if got, _ := restored.ToSlice(); !slices.Equal(got, want[1:]) {
+		t.Errorf("Expected %v, got %v", want[1:], got)
+	}
+
+	// Test Compact switches logs
+	var compacted bytes.Buffer
+	if err := names.Compact(&compacted); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	_ = names.DeleteBack()
+	restored, _ = Recover(bytes.NewReader(compacted.Bytes()), WithCodec[named](namedCodec{}))
+	if got, _ := restored.ToSlice(); !slices.Equal(got, want[:len(want)-1]) {
+		t.Errorf("Expected %v, got %v", want[:len(want)-1], got)
+	}
+
+	// Test Compact starts a log on a list without one
+	plain := FromSlice([]int{1, 2})
+	var started bytes.Buffer
+	if err := plain.Compact(&started); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	_ = plain.InsertFront(0)
+	recovered, _ = Recover[int](bytes.NewReader(started.Bytes()))
+	expectElements(t, recovered, 0, 1, 2)
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestBulk(t *testing.T) {
+	for name, options := range map[string][]Option[int]{
+		"pointer": {WithBlockSize[int](4)},
+		"index":   {WithBlockSize[int](4), WithIndexLinks[int]()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Test FromSlice allocates a single right-sized block
+			list := FromSlice([]int{3, 4, 5, 6, 7, 8}, options...)
+			checkList(t, list, []int{3, 4, 5, 6, 7, 8})
+			if list.blocks.next != nil || cap(list.blocks.nodes) != 6 {
+				t.Errorf("Expected a single block of 6 nodes")
+			}
+
+			// Test InsertFrontAll keeps the order of its arguments
+			if err := list.InsertFrontAll(0, 1, 2); err != nil {
+				t.Errorf("InsertFrontAll failed: %v", err)
+			}
+			if err := list.InsertBackAll(9, 10); err != nil {
+				t.Errorf("InsertBackAll failed: %v", err)
+			}
+			if err := list.InsertBackAll(); err != nil {
+				t.Errorf("InsertBackAll failed: %v", err)
+			}
+			expected := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+			checkList(t, list, expected)
+
+			// Test ToSlice
+			got, err := list.ToSlice()
+			if err != nil || !slices.Equal(got, expected) {
+				t.Errorf("Expected %v, got %v (%v)", expected, got, err)
+			}
+
+			if err := list.Free(); err != nil {
+				t.Errorf("Free failed: %v", err)
+			}
+			if _, err := list.ToSlice(); !errors.Is(err, ErrFreedList) {
+				t.Errorf("Expected ErrFreedList, got %v", err)
+			}
+			if err := list.InsertBackAll(1); !errors.Is(err, ErrFreedList) {
+				t.Errorf("Expected ErrFreedList, got %v", err)
+			}
+		})
+	}
+
+	// Test an empty slice
+	empty := FromSlice[int](nil)
+	if got, err := empty.ToSlice(); err != nil || len(got) != 0 {
+		t.Errorf("Expected an empty slice, got %v (%v)", got, err)
+	}
+}
+
+// expectElements checks the list holds exactly expected, walking it in
+// both directions.
+func expectElements(t *testing.T, list *XLL[int], expected ...int) {
+	t.Helper()
+	forward := slices.Collect(list.All())
+	if !slices.Equal(forward, expected) {
+		t.Errorf("Expected %v, got %v", expected, forward)
+	}
+	backward := slices.Collect(list.Backward())
+	slices.Reverse(backward)
+	if !slices.Equal(backward, expected) {
+		t.Errorf("Expected %v walking backward, got %v", expected, backward)
 	}
 }
 
@@ -223,6 +1754,327 @@ func TestConcurrentOperations(t *testing.T) {
 	}
 }
 
+func TestOverflow(t *testing.T) {
+	// Test rejecting
+	list := New[int](WithMaxSize[int](3, OverflowReject))
+	_ = list.InsertBackAll(1, 2, 3)
+	if err := list.InsertBack(4); !errors.Is(err, ErrFullList) {
+		t.Errorf("Expected ErrFullList, got %v", err)
+	}
+	if err := list.Append(FromSlice([]int{4})); !errors.Is(err, ErrFullList) {
+		t.Errorf("Expected ErrFullList, got %v", err)
+	}
+	expectElements(t, list, 1, 2, 3)
+
+	// Test dropping the oldest elements from the opposite end
+	list = New[int](WithMaxSize[int](3, OverflowDropOldest))
+	for i := 1; i <= 5; i++ {
+		if err := list.InsertBack(i); err != nil {
+			t.Fatalf("InsertBack failed: %v", err)
+		}
+	}
+	expectElements(t, list, 3, 4, 5)
+	_ = list.InsertFront(2)
+	expectElements(t, list, 2, 3, 4)
+	_ = list.InsertBackAll(5, 6)
+	expectElements(t, list, 4, 5, 6)
+	_ = list.InsertBackAll(7, 8, 9, 10)
+	expectElements(t, list, 8, 9, 10)
+	_ = list.InsertFrontAll(1, 2, 3, 4)
+	expectElements(t, list, 1, 2, 3)
+	_ = list.Append(FromSlice([]int{4, 5}))
+	expectElements(t, list, 3, 4, 5)
+	_ = list.Prepend(FromSlice([]int{1}))
+	expectElements(t, list, 1, 3, 4)
+	if err := list.InsertAt(1, 2); !errors.Is(err, ErrFullList) {
+		t.Errorf("Expected ErrFullList, got %v", err)
+	}
+
+	// Test dropping the newest elements
+	list = New[int](WithMaxSize[int](3, OverflowDropNewest), WithIndexLinks[int]())
+	for i := 1; i <= 5; i++ {
+		if err := list.InsertBack(i); err != nil {
+			t.Fatalf("InsertBack failed: %v", err)
+		}
+	}
+	expectElements(t, list, 1, 2, 3)
+	_, _ = list.PopFront()
+	_ = list.InsertFrontAll(-1, 0, 1)
+	expectElements(t, list, 1, 2, 3)
+	_, _ = list.PopBack()
+	_ = list.Append(FromSlice([]int{4, 5}))
+	expectElements(t, list, 1, 2, 4)
+	if err := list.PushBackWait(context.Background(), 5); err != nil {
+		t.Errorf("Expected PushBackWait not to wait, got %v", err)
+	}
+	expectElements(t, list, 1, 2, 4)
+
+	// Test the policy carries over to split off lists
+	list = FromSlice([]int{1, 2, 3}, WithMaxSize[int](3, OverflowDropOldest))
+	rest, _ := list.SplitAt(1)
+	_ = rest.InsertBackAll(4, 5)
+	expectElements(t, rest, 3, 4, 5)
+}
+
+func TestWait(t *testing.T) {
+	ctx := context.Background()
+
+	// Test a waiting pop receives a later insert
+	list := New[int]()
+	result := make(chan int)
+	go func() {
+		v, err := list.PopFrontWait(ctx)
+		if err != nil {
+			t.Errorf("PopFrontWait failed: %v", err)
+		}
+		result <- v
+	}()
+	time.Sleep(10 * time.Millisecond)
+	_ = list.InsertBack(7)
+	if v := <-result; v != 7 {
+		t.Errorf("Expected 7, got %d", v)
+	}
+
+	// Test cancellation and Free end the wait
+	cancelled, cancel := context.WithCancel(ctx)
+	errs := make(chan error)
+	go func() {
+		_, err := list.PopBackWait(cancelled)
+		errs <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	go func() {
+		_, err := list.PopFrontWait(ctx)
+		errs <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	_ = list.Free()
+	if err := <-errs; !errors.Is(err, ErrFreedList) {
+		t.Errorf("Expected ErrFreedList, got %v", err)
+	}
+
+	// Test a bounded list rejects inserts and makes pushes wait
+	list = New[int](WithMaxSize[int](2, OverflowReject))
+	_ = list.InsertBackAll(1, 2)
+	if err := list.InsertFront(0); !errors.Is(err, ErrFullList) {
+		t.Errorf("Expected ErrFullList, got %v", err)
+	}
+	if err := list.InsertBackAll(3); !errors.Is(err, ErrFullList) {
+		t.Errorf("Expected ErrFullList, got %v", err)
+	}
+	go func() {
+		errs <- list.PushBackWait(ctx, 3)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if v, _ := list.PopFront(); v != 1 {
+		t.Errorf("Expected 1, got %d", v)
+	}
+	if err := <-errs; err != nil {
+		t.Errorf("PushBackWait failed: %v", err)
+	}
+	expectElements(t, list, 2, 3)
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := list.PushFrontWait(timeout, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	// Test a list without locking never waits
+	list = New[int](WithoutLocking[int]())
+	if _, err := list.PopFrontWait(ctx); !errors.Is(err, ErrEmptyList) {
+		t.Errorf("Expected ErrEmptyList, got %v", err)
+	}
+}
+
+func TestWaitConcurrent(t *testing.T) {
+	ctx := context.Background()
+	list := New[int](WithMaxSize[int](8, OverflowReject), WithBlockSize[int](4))
+	producers, consumers, n := 4, 4, 1000
+	var wg sync.WaitGroup
+	var sum atomic.Int64
+
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 1; i <= n; i++ {
+				if err := list.PushBackWait(ctx, i); err != nil {
+					t.Errorf("PushBackWait failed: %v", err)
+				}
+			}
+		}()
+	}
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				v, err := list.PopFrontWait(ctx)
+				if err != nil {
+					t.Errorf("PopFrontWait failed: %v", err)
+				}
+				sum.Add(int64(v))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if expected := int64(producers * n * (n + 1) / 2); sum.Load() != expected {
+		t.Errorf("Expected sum %d, got %d", expected, sum.Load())
+	}
+	if list.Size() != 0 {
+		t.Errorf("Expected an empty list, got size %d", list.Size())
+	}
+}
+
+func TestChannels(t *testing.T) {
+	ctx := context.Background()
+
+	// Test Feed appends until the channel closes
+	list := New[int]()
+	ch := make(chan int)
+	go func() {
+		for i := 1; i <= 5; i++ {
+			ch <- i
+		}
+		close(ch)
+	}()
+	if err := list.Feed(ctx, ch); err != nil {
+		t.Errorf("Feed failed: %v", err)
+	}
+	expectElements(t, list, 1, 2, 3, 4, 5)
+
+	// Test Feed stops on cancellation
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := list.Feed(cancelled, make(chan int)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	// Test Stream sends the elements without removing them
+	var got []int
+	for v := range list.Stream(ctx) {
+		got = append(got, v)
+	}
+	if !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Expected [1 2 3 4 5], got %v", got)
+	}
+	expectElements(t, list, 1, 2, 3, 4, 5)
+
+	// Test Stream stops when the list is freed
+	stream := list.Stream(ctx)
+	<-stream
+	_ = list.Free()
+	got = nil
+	for v := range stream {
+		got = append(got, v)
+	}
+	if len(got) > 1 {
+		t.Errorf("Expected the stream to stop after Free, got %v", got)
+	}
+	if _, ok := <-list.Stream(ctx); ok {
+		t.Errorf("Expected a closed stream on a freed list")
+	}
+
+	// Test Drain consumes elements as they arrive and puts back the one
+	// it holds when cancelled
+	list = FromSlice([]int{1, 2})
+	drainCtx, cancel := context.WithCancel(ctx)
+	drain := list.Drain(drainCtx)
+	if v := <-drain; v != 1 {
+		t.Errorf("Expected 1, got %d", v)
+	}
+	_ = list.InsertBack(3)
+	if v := <-drain; v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+	for list.Size() != 0 {
+		runtime.Gosched()
+	}
+	cancel()
+	for list.Size() == 0 {
+		runtime.Gosched()
+	}
+	for range drain {
+		t.Errorf("Expected no more elements after cancelling")
+	}
+	expectElements(t, list, 3)
+
+	// Test Drain ends when the list is freed
+	drain = list.Drain(ctx)
+	<-drain
+	_ = list.Free()
+	if _, ok := <-drain; ok {
+		t.Errorf("Expected Drain to end after Free")
+	}
+}
+
+func TestConcurrentFree(t *testing.T) {
+	for round := 0; round < 50; round++ {
+		list := New[int](WithBlockSize[int](16))
+		var inserted, deleted atomic.Int64
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < 200; i++ {
+					var err error
+					switch (g + i) % 4 {
+					case 0:
+						if err = list.InsertFront(i); err == nil {
+							inserted.Add(1)
+						}
+					case 1:
+						if err = list.InsertBack(i); err == nil {
+							inserted.Add(1)
+						}
+					case 2:
+						if err = list.DeleteFront(); err == nil {
+							deleted.Add(1)
+						}
+					default:
+						if err = list.DeleteBack(); err == nil {
+							deleted.Add(1)
+						}
+					}
+					if err != nil && !errors.Is(err, ErrEmptyList) && !errors.Is(err, ErrFreedList) {
+						t.Errorf("Unexpected error: %v", err)
+					}
+				}
+			}(g)
+		}
+
+		// Free half of the lists while operations are still in flight
+		freed := round%2 == 0
+		if freed {
+			runtime.Gosched()
+			if err := list.Free(); err != nil {
+				t.Errorf("Free failed: %v", err)
+			}
+		}
+		wg.Wait()
+
+		if freed {
+			if list.Size() != 0 || list.head != 0 || list.blocks != nil {
+				t.Fatalf("Freed list still holds %d nodes", list.Size())
+			}
+			continue
+		}
+		count := 0
+		if err := list.TraverseForward(func(int) { count++ }); err != nil {
+			t.Fatalf("TraverseForward failed: %v", err)
+		}
+		if want := int(inserted.Load() - deleted.Load()); list.Size() != count || count != want {
+			t.Fatalf("Size %d, traversed %d, expected %d", list.Size(), count, want)
+		}
+	}
+}
+
 func TestGarbageCollection(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(1)